@@ -0,0 +1,286 @@
+package converter
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 오디오 전용 변환 기본 비트레이트 (kbps, 큰 순서)
+var defaultAudioOnlyBitrates = []int{192, 128, 64}
+
+// 오디오 파일 형식 검증 (팟캐스트 / 음원)
+func isAudioFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	validExtensions := []string{".mp3", ".m4a", ".wav", ".flac"}
+	for _, validExt := range validExtensions {
+		if ext == validExt {
+			return true
+		}
+	}
+	return false
+}
+
+// 쉼표로 구분된 오디오 비트레이트 목록 파싱 (빈 값은 기본값)
+func parseAudioOnlyBitrates(value string) ([]int, error) {
+	if strings.TrimSpace(value) == "" {
+		return defaultAudioOnlyBitrates, nil
+	}
+
+	var bitrates []int
+	for _, part := range strings.Split(value, ",") {
+		bitrate, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || bitrate <= 0 {
+			return nil, fmt.Errorf("잘못된 오디오 비트레이트: %s", part)
+		}
+		bitrates = append(bitrates, bitrate)
+	}
+
+	return bitrates, nil
+}
+
+// 오디오 전용 렌디션 구성 - 기본 트랙 하나를 비트레이트별로 인코딩
+func audioOnlyRenditions(bitrates []int, tracks []AudioStream, preferred string) []AudioRendition {
+	defaultIndex := defaultAudioIndex(tracks, preferred)
+
+	for _, track := range tracks {
+		if track.Index == defaultIndex {
+			return audioRenditionsForBitrates(bitrates, []AudioStream{track}, defaultIndex)
+		}
+	}
+
+	return nil
+}
+
+// 비디오 래더에 저대역폭 오디오 전용 variant 용 오디오 그룹 추가
+// 래더에 같은 비트레이트 그룹이 이미 있으면 그 그룹을 그대로 사용
+func addAudioFallback(job *ConversionJob, bitrate int, defaultIndex int) {
+	if bitrate <= 0 || len(job.AudioRenditions) == 0 {
+		return
+	}
+
+	group := fmt.Sprintf("audio_%dk", bitrate)
+	for _, audio := range job.AudioRenditions {
+		if audio.GroupID == group {
+			job.AudioFallbackGroup = group
+			return
+		}
+	}
+
+	job.AudioRenditions = append(job.AudioRenditions, audioRenditionsForBitrates([]int{bitrate}, job.Source.AudioStreams, defaultIndex)...)
+	job.AudioFallbackGroup = group
+}
+
+// 오디오 전용 fallback variant - 그룹의 기본 트랙 플레이리스트를 직접 참조
+// 비디오 variant 보다 낮은 대역폭이므로 플레이어가 회선 상태에 따라 자동으로 전환
+func audioFallbackVariant(job *ConversionJob, encodedFileName string) (VariantStream, bool) {
+	for _, audio := range job.AudioRenditions {
+		if audio.GroupID != job.AudioFallbackGroup || !audio.Default {
+			continue
+		}
+
+		uri := variantPlaylistName(encodedFileName, audio.Name)
+		peak, average := variantBandwidth(filepath.Join(job.OutputDir, uri), audio.Bitrate, audio.Bitrate)
+
+		return VariantStream{
+			URI:              uri,
+			Bandwidth:        peak,
+			AverageBandwidth: average,
+			Codecs:           []string{aacCodecString},
+			Audio:            audio.GroupID,
+		}, true
+	}
+
+	return VariantStream{}, false
+}
+
+// 오디오 전용 마스터 플레이리스트 - 비트레이트별 오디오 플레이리스트를 variant 로 직접 참조
+func buildAudioOnlyMasterPlaylist(job *ConversionJob, encodedFileName string) *MasterPlaylist {
+	master := &MasterPlaylist{Version: 3}
+	if job.SegmentType == SegmentTypeFMP4 {
+		master.Version = 7
+	}
+
+	for _, audio := range job.AudioRenditions {
+		uri := variantPlaylistName(encodedFileName, audio.Name)
+		peak, average := variantBandwidth(filepath.Join(job.OutputDir, uri), audio.Bitrate, audio.Bitrate)
+
+		master.Variants = append(master.Variants, VariantStream{
+			URI:              uri,
+			Bandwidth:        peak,
+			AverageBandwidth: average,
+			Codecs:           []string{aacCodecString},
+		})
+	}
+
+	return master
+}
+
+// 오디오 파일에 포함된 커버 이미지 (ID3 APIC / attached picture) 를 JPEG 로 추출
+func extractCoverArt(ctx context.Context, job *ConversionJob, encodedFileName string) error {
+	if job.Source.CoverArtIndex < 0 {
+		return nil
+	}
+
+	coverPath := filepath.Join(job.OutputDir, fmt.Sprintf("%s_cover.jpg", encodedFileName))
+
+	args := []string{
+		"-y",
+		"-i", job.InputFile,
+		"-map", fmt.Sprintf("0:v:%d", job.Source.CoverArtIndex),
+		"-frames:v", "1",
+		"-q:v", "2",
+		coverPath,
+	}
+
+	if err := runFFmpegStep(ctx, job, "커버 이미지", args); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(coverPath); err != nil {
+		return fmt.Errorf("커버 이미지가 생성되지 않았습니다")
+	}
+
+	job.PosterFile = coverPath
+	job.PosterFiles = []string{coverPath}
+	return nil
+}
+
+// 커버 이미지를 담는 timed ID3 이벤트 (fMP4 emsg 박스) 스킴
+const id3EventScheme = "https://aomedia.org/emsg/ID3"
+
+// 세그먼트에 삽입할 커버 이미지 최대 크기 (초과 시 포스터로만 제공)
+const maxEmbeddedCoverSize = 1 << 20
+
+// 추출한 커버 이미지를 ID3 APIC 프레임으로 각 오디오 렌디션의 첫 fMP4 세그먼트에 삽입
+// styp 뒤 첫 moof 앞에 timed ID3 emsg 박스를 넣으며, 암호화하지 않는 세그먼트에만 사용
+func embedCoverArt(job *ConversionJob, encodedFileName string) error {
+	if job.PosterFile == "" {
+		return nil
+	}
+	if job.SegmentType != SegmentTypeFMP4 {
+		return fmt.Errorf("커버 이미지 삽입은 fMP4 세그먼트에서만 지원합니다")
+	}
+
+	image, err := os.ReadFile(job.PosterFile)
+	if err != nil {
+		return fmt.Errorf("커버 이미지 읽기 오류: %v", err)
+	}
+	if len(image) > maxEmbeddedCoverSize {
+		return fmt.Errorf("커버 이미지가 너무 큽니다: %d bytes", len(image))
+	}
+
+	event := id3EventBox(id3PictureTag("image/jpeg", image))
+	for _, audio := range job.AudioRenditions {
+		segmentPath := filepath.Join(job.OutputDir, fmt.Sprintf("%s_%s_000.m4s", encodedFileName, audio.Name))
+		if err := insertBeforeFragment(segmentPath, event); err != nil {
+			return fmt.Errorf("커버 이미지 삽입 오류 (%s): %v", audio.Name, err)
+		}
+	}
+
+	job.CoverArtEmbedded = true
+	return nil
+}
+
+// 앞표지 (picture type 3) APIC 프레임 하나로 구성된 ID3v2.4 태그
+func id3PictureTag(mimeType string, image []byte) []byte {
+	var frame []byte
+	frame = append(frame, 0x00) // ISO-8859-1
+	frame = append(frame, mimeType...)
+	frame = append(frame, 0x00)
+	frame = append(frame, 0x03) // front cover
+	frame = append(frame, 0x00) // 빈 설명
+	frame = append(frame, image...)
+
+	tag := []byte{'I', 'D', '3', 0x04, 0x00, 0x00}
+	tag = append(tag, syncsafe(10+len(frame))...)
+	tag = append(tag, 'A', 'P', 'I', 'C')
+	tag = append(tag, syncsafe(len(frame))...)
+	tag = append(tag, 0x00, 0x00)
+	return append(tag, frame...)
+}
+
+// ID3v2 syncsafe 정수 (바이트당 하위 7비트 사용)
+func syncsafe(n int) []byte {
+	return []byte{byte(n>>21) & 0x7f, byte(n>>14) & 0x7f, byte(n>>7) & 0x7f, byte(n) & 0x7f}
+}
+
+// ID3 태그를 싣는 emsg (version 1) 박스 - 재생 시작 시점부터 길이 미정으로 표시
+func id3EventBox(tag []byte) []byte {
+	var body []byte
+	body = append(body, 0x01, 0x00, 0x00, 0x00)            // version 1, flags
+	body = binary.BigEndian.AppendUint32(body, 1000)       // timescale
+	body = binary.BigEndian.AppendUint64(body, 0)          // presentation_time
+	body = binary.BigEndian.AppendUint32(body, 0xffffffff) // event_duration (미정)
+	body = binary.BigEndian.AppendUint32(body, 0)          // id
+	body = append(body, id3EventScheme...)
+	body = append(body, 0x00)
+	body = append(body, 0x00) // 빈 value
+	body = append(body, tag...)
+
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	box = append(box, 'e', 'm', 's', 'g')
+	return append(box, body...)
+}
+
+// 세그먼트의 첫 moof 바로 앞 (styp / sidx 뒤) 에 박스를 삽입해 임시 파일로 작성 후 교체
+func insertBeforeFragment(path string, box []byte) error {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	offset, err := firstFragmentOffset(original)
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, 0, len(original)+len(box))
+	data = append(data, original[:offset]...)
+	data = append(data, box...)
+	data = append(data, original[offset:]...)
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// fMP4 세그먼트에서 첫 moof 박스의 시작 위치
+func firstFragmentOffset(data []byte) (int64, error) {
+	var offset int64
+	for offset+8 <= int64(len(data)) {
+		size, boxType := readBoxHeader(data[offset:])
+		if size < 8 || size > int64(len(data))-offset {
+			break
+		}
+		if boxType == "moof" {
+			return offset, nil
+		}
+		offset += size
+	}
+
+	return 0, fmt.Errorf("moof 박스를 찾을 수 없습니다")
+}
+
+// 첫 moof 앞에 있는 emsg 박스 크기 합계 (삽입한 메타데이터는 미디어 비트레이트에서 제외)
+func leadingEventSize(data []byte) int64 {
+	var offset, total int64
+	for offset+8 <= int64(len(data)) {
+		size, boxType := readBoxHeader(data[offset:])
+		if size < 8 || size > int64(len(data))-offset || boxType == "moof" {
+			break
+		}
+		if boxType == "emsg" {
+			total += size
+		}
+		offset += size
+	}
+
+	return total
+}
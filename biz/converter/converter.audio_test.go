@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestInsertBeforeFragment(t *testing.T) {
+	styp := testBox("styp", []byte("msdh"), make([]byte, 4))
+	sidx := testBox("sidx", make([]byte, 24))
+	fragment := append(testBox("moof", testFullBox("mfhd", 0, 1)), testBox("mdat", make([]byte, 32))...)
+	event := id3EventBox(id3PictureTag("image/jpeg", []byte{0xFF, 0xD8, 0xFF, 0xD9}))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "styp 뒤 moof 앞",
+			data: joinTestPackets(styp, fragment),
+			want: joinTestPackets(styp, event, fragment),
+		},
+		{
+			name: "sidx 도 emsg 앞에 유지",
+			data: joinTestPackets(styp, sidx, fragment),
+			want: joinTestPackets(styp, sidx, event, fragment),
+		},
+		{
+			name: "styp 없는 세그먼트",
+			data: fragment,
+			want: joinTestPackets(event, fragment),
+		},
+		{
+			name:    "moof 없는 세그먼트",
+			data:    joinTestPackets(styp, testBox("mdat", make([]byte, 32))),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestSegment(t, "segment.m4s", tt.data)
+
+			err := insertBeforeFragment(path, event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("insertBeforeFragment() 오류 = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("삽입 결과가 다릅니다:\n got %x\nwant %x", got, tt.want)
+			}
+			if size := leadingEventSize(got); size != int64(len(event)) {
+				t.Errorf("leadingEventSize() = %d, want %d", size, len(event))
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package converter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 시작 시 FFmpeg 확인 제한 시간
+const capabilityTimeout = 30 * time.Second
+
+// FFmpeg / FFprobe 바이너리 정보와 지원 기능
+type FFmpegCapabilities struct {
+	Path         string `json:"path"`
+	Version      string `json:"version"`
+	ProbePath    string `json:"probe_path"`
+	ProbeVersion string `json:"probe_version"`
+
+	Encoders  map[string]bool `json:"-"`
+	Filters   map[string]bool `json:"-"`
+	Protocols map[string]bool `json:"-"`
+	Muxers    map[string]bool `json:"-"`
+}
+
+// 시작 시 확인한 FFmpeg 정보 (확인 전에는 nil)
+var ffmpegCapabilities *FFmpegCapabilities
+
+// 시작 시 확인한 FFmpeg 정보 조회 (헬스 체크용)
+func Capabilities() *FFmpegCapabilities {
+	return ffmpegCapabilities
+}
+
+// FFmpeg / FFprobe 기능 확인 후 프로파일에 필요한 인코더 / 필터 / 프로토콜 / 먹서가 모두 있는지 검증
+// 바이너리가 없거나 기능이 부족하면 첫 작업이 실패하기 전에 시작을 중단할 수 있도록 오류 반환
+func CheckFFmpeg(ctx context.Context, profiles ...*EncodingProfile) (*FFmpegCapabilities, error) {
+	ctx, cancel := context.WithTimeout(ctx, capabilityTimeout)
+	defer cancel()
+
+	capabilities, err := probeCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		if missing := capabilities.missing(profileRequirements(profile)); len(missing) > 0 {
+			return nil, fmt.Errorf("FFmpeg 에 프로파일 %s 에 필요한 기능이 없습니다 (%s): %s", profile.Name, capabilities.Path, strings.Join(missing, ", "))
+		}
+	}
+
+	log.Printf("FFmpeg 확인: %s (%s), FFprobe: %s (%s), 인코더 %d / 필터 %d / 프로토콜 %d / 먹서 %d",
+		capabilities.Version, capabilities.Path, capabilities.ProbeVersion, capabilities.ProbePath,
+		len(capabilities.Encoders), len(capabilities.Filters), len(capabilities.Protocols), len(capabilities.Muxers))
+
+	ffmpegCapabilities = capabilities
+	return capabilities, nil
+}
+
+// -version / -encoders / -filters / -protocols / -muxers 출력으로 지원 기능 수집
+func probeCapabilities(ctx context.Context) (*FFmpegCapabilities, error) {
+	path, err := exec.LookPath(ffmpegBinary())
+	if err != nil {
+		return nil, fmt.Errorf("FFmpeg 바이너리를 찾을 수 없습니다: %v", err)
+	}
+	probePath, err := exec.LookPath(ffprobeBinary())
+	if err != nil {
+		return nil, fmt.Errorf("FFprobe 바이너리를 찾을 수 없습니다: %v", err)
+	}
+
+	capabilities := &FFmpegCapabilities{Path: path, ProbePath: probePath}
+
+	outputs := make(map[string]string)
+	for _, flag := range []string{"-version", "-encoders", "-filters", "-protocols", "-muxers"} {
+		output, runErr := newCommand(ctx, path, "-hide_banner", flag).Output()
+		if runErr != nil {
+			return nil, fmt.Errorf("FFmpeg %s 실행 오류: %v", flag, runErr)
+		}
+		outputs[flag] = string(output)
+	}
+
+	probeOutput, err := newCommand(ctx, probePath, "-version").Output()
+	if err != nil {
+		return nil, fmt.Errorf("FFprobe -version 실행 오류: %v", err)
+	}
+
+	capabilities.Version = parseVersion(outputs["-version"])
+	capabilities.ProbeVersion = parseVersion(string(probeOutput))
+	capabilities.Encoders = parseEncoders(outputs["-encoders"])
+	capabilities.Filters = parseFilters(outputs["-filters"])
+	capabilities.Protocols = parseProtocols(outputs["-protocols"])
+	capabilities.Muxers = parseMuxers(outputs["-muxers"])
+
+	return capabilities, nil
+}
+
+// 첫 줄 "ffmpeg version 6.1.1 Copyright ..." 에서 버전 추출
+func parseVersion(output string) string {
+	firstLine, _, _ := strings.Cut(output, "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) >= 3 && fields[1] == "version" {
+		return fields[2]
+	}
+	return strings.TrimSpace(firstLine)
+}
+
+// -encoders 출력 - " V....D libx264  libx264 H.264 ..." 형식, "------" 구분선 이후가 목록
+func parseEncoders(output string) map[string]bool {
+	encoders := make(map[string]bool)
+	started := false
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if !started {
+			started = strings.HasPrefix(fields[0], "---")
+			continue
+		}
+		if len(fields) >= 2 {
+			encoders[fields[1]] = true
+		}
+	}
+
+	return encoders
+}
+
+// -filters 출력 - " TSC scale  V->V  Scale the input ..." 형식, 입출력 표기(->)가 있는 줄만 목록
+func parseFilters(output string) map[string]bool {
+	filters := make(map[string]bool)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 3 && strings.Contains(fields[2], "->") {
+			filters[fields[1]] = true
+		}
+	}
+
+	return filters
+}
+
+// -protocols 출력 - "Input:" / "Output:" 아래 한 줄에 하나씩
+func parseProtocols(output string) map[string]bool {
+	protocols := make(map[string]bool)
+	inList := false
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "Input:" || line == "Output:":
+			inList = true
+		case inList && line != "" && !strings.Contains(line, " "):
+			protocols[line] = true
+		}
+	}
+
+	return protocols
+}
+
+// -muxers 출력 - "  E hls  Apple HTTP Live Streaming" 형식, "--" 구분선 이후가 목록 (이름은 쉼표로 여러 개일 수 있음)
+func parseMuxers(output string) map[string]bool {
+	muxers := make(map[string]bool)
+	started := false
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if !started {
+			started = strings.HasPrefix(fields[0], "--")
+			continue
+		}
+		if len(fields) >= 2 && strings.Contains(fields[0], "E") {
+			for _, name := range strings.Split(fields[1], ",") {
+				muxers[name] = true
+			}
+		}
+	}
+
+	return muxers
+}
+
+// 프로파일에 필요한 기능 목록
+type capabilityRequirements struct {
+	Encoders  []string
+	Filters   []string
+	Protocols []string
+	Muxers    []string
+}
+
+// 프로파일 설정에 따라 변환 과정에서 사용하는 인코더 / 필터 / 프로토콜
+// 세그먼트 형식 / 암호화는 메시지마다 바꿀 수 있으므로 프로파일 기본값과 관계없이 모두 확인
+func profileRequirements(profile *EncodingProfile) capabilityRequirements {
+	req := capabilityRequirements{
+		// 래더 인코딩 / 오디오 / 자막 / 커버 이미지
+		Encoders: []string{profile.VideoCodec, profile.AudioCodec, "webvtt", "mjpeg"},
+		Filters:  []string{"split", "scale", "setsar"},
+		// 출력 파일 / -progress pipe:1 / 단일 키 AES-128 (키 순환은 인코딩 후 직접 암호화)
+		Protocols: []string{"file", "pipe", "crypto"},
+		// HLS 출력 / 세그먼트 컨테이너 / 자막 세그먼트 / 커버 이미지
+		// 커버 이미지 / 추가 코덱 / DASH 는 fMP4 를 강제 (DASH 매니페스트는 직접 작성하므로 dash 먹서 불필요)
+		Muxers: []string{"hls", "mpegts", "mp4", "webvtt", "image2"},
+	}
+
+	req.Encoders = append(req.Encoders, profile.ExtraCodecs...)
+
+	if profile.Loudness.Target != 0 {
+		req.Filters = append(req.Filters, "loudnorm")
+		req.Muxers = append(req.Muxers, "null")
+	}
+	if profile.Sprites.Interval > 0 {
+		req.Filters = append(req.Filters, "fps", "tile")
+		if profile.Sprites.Format == "webp" {
+			req.Encoders = append(req.Encoders, "libwebp")
+		}
+	}
+	if len(profile.PosterWidths) > 0 {
+		req.Filters = append(req.Filters, "thumbnail", "signalstats", "metadata")
+	}
+	if profile.Preview.Duration > 0 {
+		req.Encoders = append(req.Encoders, "libwebp")
+		req.Filters = append(req.Filters, "fps", "setpts", "concat")
+		req.Muxers = append(req.Muxers, "webp")
+	}
+
+	return req
+}
+
+// 지원하지 않는 기능 목록 ("encoder:libx264" / "muxer:hls" 형식)
+func (c *FFmpegCapabilities) missing(req capabilityRequirements) []string {
+	var missing []string
+
+	check := func(kind string, names []string, supported map[string]bool) {
+		for _, name := range names {
+			label := kind + ":" + name
+			if !supported[name] && !slices.Contains(missing, label) {
+				missing = append(missing, label)
+			}
+		}
+	}
+
+	check("encoder", req.Encoders, c.Encoders)
+	check("filter", req.Filters, c.Filters)
+	check("protocol", req.Protocols, c.Protocols)
+	check("muxer", req.Muxers, c.Muxers)
+
+	return missing
+}
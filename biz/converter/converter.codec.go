@@ -0,0 +1,310 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// 비디오 코덱 종류
+const (
+	CodecH264 = "h264"
+	CodecHEVC = "hevc"
+	CodecAV1  = "av1"
+	CodecVP9  = "vp9"
+)
+
+// 인코더별 코덱 종류
+var encoderCodecs = map[string]string{
+	"libx264":    CodecH264,
+	"libx265":    CodecHEVC,
+	"libsvtav1":  CodecAV1,
+	"libaom-av1": CodecAV1,
+	"libvpx-vp9": CodecVP9,
+}
+
+// 코덱 이름으로 지정했을 때 사용할 인코더
+var codecEncoders = map[string]string{
+	CodecHEVC: "libx265",
+	CodecAV1:  "libsvtav1",
+	CodecVP9:  "libvpx-vp9",
+}
+
+// 같은 화질에 필요한 H.264 대비 비트레이트 비율
+var codecBitrateRatio = map[string]float64{
+	CodecHEVC: 0.6,
+	CodecAV1:  0.5,
+	CodecVP9:  0.65,
+}
+
+// 코덱 레벨 - 최대 화면 크기 (픽셀) / 초당 최대 픽셀 수 기준
+type codecLevel struct {
+	Name          string
+	Idc           int // CODECS 문자열에 들어가는 값
+	MaxPicture    int
+	MaxSampleRate int64
+}
+
+// H.264 레벨 (level_idc = 레벨 x 10, 크기 / 처리량은 매크로블록 수 x 256)
+var h264Levels = []codecLevel{
+	{"1.0", 10, 99 * 256, 1485 * 256},
+	{"1.1", 11, 396 * 256, 3000 * 256},
+	{"1.2", 12, 396 * 256, 6000 * 256},
+	{"1.3", 13, 396 * 256, 11880 * 256},
+	{"2.0", 20, 396 * 256, 11880 * 256},
+	{"2.1", 21, 792 * 256, 19800 * 256},
+	{"2.2", 22, 1620 * 256, 20250 * 256},
+	{"3.0", 30, 1620 * 256, 40500 * 256},
+	{"3.1", 31, 3600 * 256, 108000 * 256},
+	{"3.2", 32, 5120 * 256, 216000 * 256},
+	{"4.0", 40, 8192 * 256, 245760 * 256},
+	{"4.1", 41, 8192 * 256, 245760 * 256},
+	{"4.2", 42, 8704 * 256, 522240 * 256},
+	{"5.0", 50, 22080 * 256, 589824 * 256},
+	{"5.1", 51, 36864 * 256, 983040 * 256},
+	{"5.2", 52, 36864 * 256, 2073600 * 256},
+	{"6.0", 60, 139264 * 256, 4177920 * 256},
+	{"6.1", 61, 139264 * 256, 8355840 * 256},
+	{"6.2", 62, 139264 * 256, 16711680 * 256},
+}
+
+// HEVC Main tier 레벨 (general_level_idc = 레벨 x 30)
+var hevcLevels = []codecLevel{
+	{"1.0", 30, 36864, 552960},
+	{"2.0", 60, 122880, 3686400},
+	{"2.1", 63, 245760, 7372800},
+	{"3.0", 90, 552960, 16588800},
+	{"3.1", 93, 983040, 33177600},
+	{"4.0", 120, 2228224, 66846720},
+	{"4.1", 123, 2228224, 133693440},
+	{"5.0", 150, 8912896, 267386880},
+	{"5.1", 153, 8912896, 534773760},
+	{"5.2", 156, 8912896, 1069547520},
+	{"6.0", 180, 35651584, 1069547520},
+	{"6.1", 183, 35651584, 2139095040},
+	{"6.2", 186, 35651584, 4278190080},
+}
+
+// AV1 레벨 (seq_level_idx)
+var av1Levels = []codecLevel{
+	{"2.0", 0, 147456, 4423680},
+	{"2.1", 1, 278784, 8363520},
+	{"3.0", 4, 665856, 19975680},
+	{"3.1", 5, 1065024, 31950720},
+	{"4.0", 8, 2359296, 70778880},
+	{"4.1", 9, 2359296, 141557760},
+	{"5.0", 12, 8912896, 267386880},
+	{"5.1", 13, 8912896, 534773760},
+	{"5.2", 14, 8912896, 1069547520},
+	{"5.3", 15, 8912896, 1069547520},
+	{"6.0", 16, 35651584, 1069547520},
+	{"6.1", 17, 35651584, 2139095040},
+	{"6.2", 18, 35651584, 4278190080},
+	{"6.3", 19, 35651584, 4278190080},
+}
+
+// VP9 레벨 (레벨 x 10)
+var vp9Levels = []codecLevel{
+	{"1.0", 10, 36864, 829440},
+	{"1.1", 11, 73728, 2764800},
+	{"2.0", 20, 122880, 4608000},
+	{"2.1", 21, 245760, 9216000},
+	{"3.0", 30, 552960, 20736000},
+	{"3.1", 31, 983040, 36864000},
+	{"4.0", 40, 2228224, 83558400},
+	{"4.1", 41, 2228224, 160432128},
+	{"5.0", 50, 8912896, 311951360},
+	{"5.1", 51, 8912896, 588251136},
+	{"5.2", 52, 8912896, 1176502272},
+	{"6.0", 60, 35651584, 1176502272},
+	{"6.1", 61, 35651584, 2353004544},
+	{"6.2", 62, 35651584, 4706009088},
+}
+
+var codecLevels = map[string][]codecLevel{
+	CodecHEVC: hevcLevels,
+	CodecAV1:  av1Levels,
+	CodecVP9:  vp9Levels,
+}
+
+// 렌디션의 비디오 코덱 종류 (인코더를 지정하지 않았으면 H.264)
+func (r Rendition) codec() string {
+	if codec, ok := encoderCodecs[r.Codec]; ok {
+		return codec
+	}
+	return CodecH264
+}
+
+// 추가 코덱 설정값을 인코더 이름으로 정리 ("hevc" -> "libx265")
+// H.264 는 기본 래더이므로 제외하고, 같은 코덱을 두 번 지정할 수 없음
+func normalizeExtraCodecs(values []string) ([]string, error) {
+	var encoders []string
+	seen := make(map[string]bool)
+
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		encoder := value
+		if mapped, ok := codecEncoders[value]; ok {
+			encoder = mapped
+		}
+
+		codec, ok := encoderCodecs[encoder]
+		if !ok || codec == CodecH264 {
+			return nil, fmt.Errorf("지원하지 않는 추가 코덱: %s", value)
+		}
+		if seen[codec] {
+			return nil, fmt.Errorf("중복된 추가 코덱: %s", value)
+		}
+		seen[codec] = true
+
+		encoders = append(encoders, encoder)
+	}
+
+	return encoders, nil
+}
+
+// H.264 래더를 추가 코덱별로 복제 - 코덱 효율만큼 비트레이트를 낮추고 해상도 / 프레임레이트로 레벨 결정
+func extraCodecRenditions(ladder []Rendition, encoders []string, frameRate float64) []Rendition {
+	if frameRate <= 0 {
+		frameRate = 30
+	}
+
+	var extra []Rendition
+	for _, encoder := range encoders {
+		codec := encoderCodecs[encoder]
+
+		for _, r := range ladder {
+			rendition := r
+			rendition.Name = fmt.Sprintf("%s_%s", r.Name, codec)
+			rendition.Codec = encoder
+			rendition.Profile = "main"
+			rendition.Level = selectCodecLevel(codecLevels[codec], r.Width, r.Height, frameRate).Name
+			scaleBitrates(&rendition, codecBitrateRatio[codec])
+
+			extra = append(extra, rendition)
+		}
+	}
+
+	return extra
+}
+
+// H.264 렌디션 레벨을 해상도 / 프레임레이트를 수용하는 레벨 이상으로 올림 (설정된 레벨은 하한)
+// 60fps 원본의 720p 를 3.1 로 표시하는 식의 규격 위반을 막기 위해 -level 과 CODECS 에 같은 값 사용
+func fitH264Levels(ladder []Rendition, frameRate float64) {
+	if frameRate <= 0 {
+		frameRate = 30
+	}
+
+	for i := range ladder {
+		// H.264 는 16x16 매크로블록 단위로 크기를 계산
+		width, height := (ladder[i].Width+15)/16*16, (ladder[i].Height+15)/16*16
+		required := selectCodecLevel(h264Levels, width, height, frameRate)
+		if required.Idc > h264LevelIdc(ladder[i].Level) {
+			ladder[i].Level = required.Name
+		}
+	}
+}
+
+// 해상도 / 프레임레이트를 수용하는 가장 낮은 레벨
+func selectCodecLevel(levels []codecLevel, width, height int, frameRate float64) codecLevel {
+	picture := width * height
+	sampleRate := int64(math.Ceil(float64(picture) * frameRate))
+
+	for _, level := range levels {
+		if picture <= level.MaxPicture && sampleRate <= level.MaxSampleRate {
+			return level
+		}
+	}
+	return levels[len(levels)-1]
+}
+
+// 레벨 이름에 해당하는 CODECS 값
+func codecLevelIdc(levels []codecLevel, name string) int {
+	for _, level := range levels {
+		if level.Name == name {
+			return level.Idc
+		}
+	}
+	return levels[len(levels)-1].Idc
+}
+
+// 렌디션의 RFC 6381 CODECS 문자열
+// HEVC: Main 프로파일 / Main tier / 프로그레시브 (hvc1.1.6.L93.B0)
+// AV1: Main 프로파일 / Main tier / 8비트 (av01.0.08M.08)
+// VP9: 프로파일 0 / 8비트 (vp09.00.40.08)
+func videoCodecString(r Rendition) string {
+	switch r.codec() {
+	case CodecHEVC:
+		return fmt.Sprintf("hvc1.1.6.L%d.B0", codecLevelIdc(hevcLevels, r.Level))
+	case CodecAV1:
+		return fmt.Sprintf("av01.0.%02dM.08", codecLevelIdc(av1Levels, r.Level))
+	case CodecVP9:
+		return fmt.Sprintf("vp09.00.%02d.08", codecLevelIdc(vp9Levels, r.Level))
+	default:
+		return h264CodecString(r.Profile, r.Level)
+	}
+}
+
+// 코덱별 인코더 옵션 (스트림 번호 i 기준)
+// 속도 프리셋은 x264 / x265 에만 적용하고, AV1 / VP9 은 실용적인 속도 설정과 키프레임 간격을 지정
+func videoEncoderArgs(i int, r Rendition, profile *EncodingProfile, frameRate float64) []string {
+	args := []string{fmt.Sprintf("-b:v:%d", i), fmt.Sprintf("%dk", r.VideoBitrate)}
+
+	// SVT-AV1 / libaom 의 최대 비트레이트는 CRF 모드에서만 사용 가능
+	if r.codec() != CodecAV1 {
+		args = append(args,
+			fmt.Sprintf("-maxrate:v:%d", i), fmt.Sprintf("%dk", r.MaxRate),
+			fmt.Sprintf("-bufsize:v:%d", i), fmt.Sprintf("%dk", r.BufSize),
+		)
+	}
+
+	switch r.codec() {
+	case CodecH264:
+		args = append(args,
+			fmt.Sprintf("-profile:v:%d", i), r.Profile,
+			fmt.Sprintf("-level:v:%d", i), r.Level,
+		)
+	case CodecHEVC:
+		// Apple 기기는 hvc1 태그만 재생, 강제 키프레임은 IDR 로 만들어 세그먼트 단독 디코딩 보장
+		// CODECS 문자열과 같은 레벨로 인코딩하도록 레벨 지정
+		args = append(args,
+			fmt.Sprintf("-profile:v:%d", i), r.Profile,
+			fmt.Sprintf("-tag:v:%d", i), "hvc1",
+			fmt.Sprintf("-forced-idr:v:%d", i), "1",
+			fmt.Sprintf("-x265-params:v:%d", i), "level-idc="+r.Level,
+		)
+	case CodecAV1:
+		if r.Codec == "libaom-av1" {
+			args = append(args,
+				fmt.Sprintf("-cpu-used:v:%d", i), "6",
+				fmt.Sprintf("-row-mt:v:%d", i), "1",
+				fmt.Sprintf("-aom-params:v:%d", i), fmt.Sprintf("target-seq-level-idx=%d", codecLevelIdc(av1Levels, r.Level)),
+			)
+		} else {
+			// SVT-AV1 레벨은 레벨 x 10 (4.0 -> 40)
+			args = append(args, fmt.Sprintf("-svtav1-params:v:%d", i), "level="+strings.ReplaceAll(r.Level, ".", ""))
+		}
+	case CodecVP9:
+		args = append(args,
+			fmt.Sprintf("-deadline:v:%d", i), "good",
+			fmt.Sprintf("-cpu-used:v:%d", i), "4",
+			fmt.Sprintf("-row-mt:v:%d", i), "1",
+			fmt.Sprintf("-level:v:%d", i), r.Level,
+		)
+	}
+
+	if profile.Preset != "" && (r.codec() == CodecH264 || r.codec() == CodecHEVC) {
+		args = append(args, fmt.Sprintf("-preset:v:%d", i), profile.Preset)
+	}
+
+	// AV1 / VP9 인코더는 자체 키프레임 간격도 세그먼트 경계에 맞춤
+	if (r.codec() == CodecAV1 || r.codec() == CodecVP9) && frameRate > 0 {
+		args = append(args, fmt.Sprintf("-g:v:%d", i), fmt.Sprintf("%d", int(math.Round(frameRate*float64(profile.GOP)))))
+	}
+
+	return args
+}
@@ -0,0 +1,125 @@
+package converter
+
+import "testing"
+
+func TestSelectCodecLevel(t *testing.T) {
+	tests := []struct {
+		name          string
+		levels        []codecLevel
+		width, height int
+		frameRate     float64
+		want          string
+	}{
+		{"H.264 720p30", h264Levels, 1280, 720, 30, "3.1"},
+		{"H.264 720p60", h264Levels, 1280, 720, 60, "3.2"},
+		{"H.264 1080p30 (매크로블록 정렬)", h264Levels, 1920, 1088, 30, "4.0"},
+		{"H.264 1080p60", h264Levels, 1920, 1088, 60, "4.2"},
+		{"HEVC 720p30", hevcLevels, 1280, 720, 30, "3.1"},
+		{"HEVC 1080p30", hevcLevels, 1920, 1080, 30, "4.0"},
+		{"HEVC 1080p60", hevcLevels, 1920, 1080, 60, "4.1"},
+		{"HEVC 2160p30", hevcLevels, 3840, 2160, 30, "5.0"},
+		{"HEVC 최고 레벨 초과", hevcLevels, 16384, 16384, 120, "6.2"},
+		{"AV1 720p30", av1Levels, 1280, 720, 30, "3.1"},
+		{"AV1 1080p30", av1Levels, 1920, 1080, 30, "4.0"},
+		{"AV1 1080p60", av1Levels, 1920, 1080, 60, "4.1"},
+		{"VP9 360p30", vp9Levels, 640, 360, 30, "2.1"},
+		{"VP9 720p30", vp9Levels, 1280, 720, 30, "3.1"},
+		{"VP9 1080p60", vp9Levels, 1920, 1080, 60, "4.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectCodecLevel(tt.levels, tt.width, tt.height, tt.frameRate)
+			if got.Name != tt.want {
+				t.Errorf("selectCodecLevel(%dx%d@%g) = %s, want %s", tt.width, tt.height, tt.frameRate, got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitH264Levels(t *testing.T) {
+	tests := []struct {
+		name      string
+		rendition Rendition
+		frameRate float64
+		want      string
+	}{
+		{"설정 레벨로 충분하면 유지", renditionPresets["1080p"], 30, "4.1"},
+		{"60fps 1080p 는 4.2", renditionPresets["1080p"], 60, "4.2"},
+		{"60fps 720p 는 3.2", renditionPresets["720p"], 60, "3.2"},
+		{"854x480 30fps 는 3.0 초과", renditionPresets["480p"], 30, "3.1"},
+		{"프레임레이트를 모르면 30fps 기준", renditionPresets["720p"], 0, "3.1"},
+		{"설정 레벨이 더 높으면 유지", Rendition{Width: 640, Height: 360, Level: "4.0"}, 30, "4.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ladder := []Rendition{tt.rendition}
+			fitH264Levels(ladder, tt.frameRate)
+			if ladder[0].Level != tt.want {
+				t.Errorf("레벨 = %s, want %s", ladder[0].Level, tt.want)
+			}
+		})
+	}
+}
+
+func TestVideoCodecString(t *testing.T) {
+	tests := []struct {
+		name      string
+		rendition Rendition
+		want      string
+	}{
+		{"H.264 High 4.1", Rendition{Profile: "high", Level: "4.1"}, "avc1.640029"},
+		{"H.264 Main 3.1", Rendition{Profile: "main", Level: "3.1"}, "avc1.4D401F"},
+		{"H.264 Baseline 3.0", Rendition{Profile: "baseline", Level: "3.0"}, "avc1.42E01E"},
+		{"H.264 인코더 지정", Rendition{Codec: "libx264", Profile: "high", Level: "4.2"}, "avc1.64002A"},
+		{"HEVC 3.1", Rendition{Codec: "libx265", Profile: "main", Level: "3.1"}, "hvc1.1.6.L93.B0"},
+		{"HEVC 5.0", Rendition{Codec: "libx265", Profile: "main", Level: "5.0"}, "hvc1.1.6.L150.B0"},
+		{"AV1 SVT 4.0", Rendition{Codec: "libsvtav1", Level: "4.0"}, "av01.0.08M.08"},
+		{"AV1 libaom 3.1", Rendition{Codec: "libaom-av1", Level: "3.1"}, "av01.0.05M.08"},
+		{"AV1 5.1", Rendition{Codec: "libsvtav1", Level: "5.1"}, "av01.0.13M.08"},
+		{"VP9 4.1", Rendition{Codec: "libvpx-vp9", Level: "4.1"}, "vp09.00.41.08"},
+		{"VP9 3.0", Rendition{Codec: "libvpx-vp9", Level: "3.0"}, "vp09.00.30.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := videoCodecString(tt.rendition); got != tt.want {
+				t.Errorf("videoCodecString() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVideoEncoderArgsLevel(t *testing.T) {
+	profile := &EncodingProfile{GOP: 2}
+
+	tests := []struct {
+		name      string
+		rendition Rendition
+		option    string
+		want      string
+	}{
+		{"H.264", Rendition{Codec: "libx264", Profile: "high", Level: "4.2"}, "-level:v:1", "4.2"},
+		{"x265", Rendition{Codec: "libx265", Profile: "main", Level: "4.1"}, "-x265-params:v:1", "level-idc=4.1"},
+		{"SVT-AV1", Rendition{Codec: "libsvtav1", Level: "4.0"}, "-svtav1-params:v:1", "level=40"},
+		{"libaom", Rendition{Codec: "libaom-av1", Level: "4.0"}, "-aom-params:v:1", "target-seq-level-idx=8"},
+		{"VP9", Rendition{Codec: "libvpx-vp9", Level: "3.1"}, "-level:v:1", "3.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := videoEncoderArgs(1, tt.rendition, profile, 30)
+
+			got := ""
+			for i := 0; i+1 < len(args); i++ {
+				if args[i] == tt.option {
+					got = args[i+1]
+				}
+			}
+			if got != tt.want {
+				t.Errorf("%s = %q, want %q (%v)", tt.option, got, tt.want, args)
+			}
+		})
+	}
+}
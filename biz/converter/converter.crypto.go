@@ -0,0 +1,270 @@
+package converter
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/donghquinn/hls_converter/configs"
+	"github.com/donghquinn/hls_converter/database"
+	"github.com/donghquinn/hls_converter/utils"
+)
+
+// HLS 암호화 방식
+const (
+	EncryptionNone   = "none"
+	EncryptionAES128 = "aes-128"
+)
+
+// 암호화 방식 값 검증 (빈 값은 암호화 없음)
+func normalizeEncryption(encryption string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(encryption)) {
+	case "", EncryptionNone:
+		return EncryptionNone, nil
+	case EncryptionAES128, "aes128":
+		return EncryptionAES128, nil
+	default:
+		return "", fmt.Errorf("지원하지 않는 암호화 방식: %s", encryption)
+	}
+}
+
+// 영상별 콘텐츠 키 - 키 순환 시 적용되는 세그먼트 구간 포함
+type contentKey struct {
+	Key          []byte
+	IV           []byte
+	URI          string
+	Index        int
+	FirstSegment int
+	LastSegment  int
+}
+
+// 128비트 키 / IV 생성
+func newContentKey(uri string) (*contentKey, error) {
+	key := make([]byte, 16)
+	iv := make([]byte, 16)
+
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("암호화 키 생성 오류: %v", err)
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("암호화 IV 생성 오류: %v", err)
+	}
+
+	return &contentKey{Key: key, IV: iv, URI: uri}, nil
+}
+
+// 키 전달 엔드포인트 URI 템플릿 치환 ({userId}, {videoSeq}, {keyIndex})
+// 키 순환 시 템플릿에 {keyIndex} 가 없으면 쿼리 파라미터로 추가
+func keyURIFor(job *ConversionJob, keyIndex int, rotating bool) (string, error) {
+	template := configs.ConverterConfig.KeyURITemplate
+	if template == "" {
+		return "", fmt.Errorf("HLS_KEY_URI 가 설정되지 않았습니다")
+	}
+
+	if rotating && !strings.Contains(template, "{keyIndex}") {
+		separator := "?"
+		if strings.Contains(template, "?") {
+			separator = "&"
+		}
+		template += separator + "keyIndex={keyIndex}"
+	}
+
+	replacer := strings.NewReplacer(
+		"{userId}", job.ID,
+		"{videoSeq}", job.VideoSeq,
+		"{keyIndex}", strconv.Itoa(keyIndex),
+	)
+
+	return replacer.Replace(template), nil
+}
+
+// FFmpeg -hls_key_info_file 용 파일 작성
+// 1행: 키 URI, 2행: 키 파일 경로, 3행: IV (hex)
+// 키 파일은 공개 출력 디렉터리가 아닌 임시 디렉터리에 저장
+func writeKeyInfoFile(dir string, key *contentKey) (string, error) {
+	keyPath := filepath.Join(dir, "enc.key")
+	if err := os.WriteFile(keyPath, key.Key, 0600); err != nil {
+		return "", err
+	}
+
+	keyInfo := fmt.Sprintf("%s\n%s\n%s\n", key.URI, keyPath, hex.EncodeToString(key.IV))
+	keyInfoPath := filepath.Join(dir, "enc.keyinfo")
+	if err := os.WriteFile(keyInfoPath, []byte(keyInfo), 0600); err != nil {
+		return "", err
+	}
+
+	return keyInfoPath, nil
+}
+
+// 키 순환 암호화 - 인코딩된 세그먼트를 N개 단위로 서로 다른 키로 암호화하고
+// 미디어 플레이리스트의 각 구간 시작 위치에 EXT-X-KEY 삽입
+// (모든 렌디션은 세그먼트 경계가 같으므로 같은 키 순서를 공유)
+func encryptWithRotation(job *ConversionJob, encodedFileName string, rotationSegments int) ([]*contentKey, error) {
+	names := make([]string, 0, len(job.Renditions)+len(job.AudioRenditions))
+	for _, r := range job.Renditions {
+		names = append(names, r.Name)
+	}
+	for _, a := range job.AudioRenditions {
+		names = append(names, a.Name)
+	}
+
+	// 가장 긴 렌디션 기준으로 필요한 키 개수 계산
+	segmentCount := 0
+	for _, name := range names {
+		segments, err := parseMediaPlaylist(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, name)))
+		if err != nil {
+			return nil, err
+		}
+		segmentCount = max(segmentCount, len(segments))
+	}
+
+	var keys []*contentKey
+	for first := 0; first < segmentCount; first += rotationSegments {
+		uri, err := keyURIFor(job, len(keys), true)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := newContentKey(uri)
+		if err != nil {
+			return nil, err
+		}
+		key.Index = len(keys)
+		key.FirstSegment = first
+		key.LastSegment = min(first+rotationSegments, segmentCount) - 1
+		keys = append(keys, key)
+	}
+
+	for _, name := range names {
+		if err := encryptMediaPlaylist(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, name)), keys, rotationSegments); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// 미디어 플레이리스트의 세그먼트 암호화 및 EXT-X-KEY 삽입
+func encryptMediaPlaylist(playlistPath string, keys []*contentKey, rotationSegments int) error {
+	content, err := os.ReadFile(playlistPath)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(playlistPath)
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	output := make([]string, 0, len(lines)+len(keys))
+	segmentIndex := 0
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// 새 키 구간의 첫 세그먼트 앞에 EXT-X-KEY 기록 (EXT-X-MAP 초기화 세그먼트는 암호화하지 않음)
+		if strings.HasPrefix(trimmed, "#EXTINF:") && segmentIndex%rotationSegments == 0 {
+			key := keys[segmentIndex/rotationSegments]
+			output = append(output, fmt.Sprintf("#EXT-X-KEY:METHOD=AES-128,URI=%s,IV=0x%s", quotedString(key.URI), hex.EncodeToString(key.IV)))
+		}
+
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			key := keys[segmentIndex/rotationSegments]
+			if err := encryptSegmentFile(filepath.Join(dir, trimmed), key); err != nil {
+				return err
+			}
+			segmentIndex++
+		}
+
+		output = append(output, line)
+	}
+
+	return os.WriteFile(playlistPath, []byte(strings.Join(output, "\n")+"\n"), 0644)
+}
+
+// 세그먼트 파일 전체를 AES-128-CBC (PKCS7) 로 암호화
+func encryptSegmentFile(segmentPath string, key *contentKey) error {
+	plain, err := os.ReadFile(segmentPath)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(key.Key)
+	if err != nil {
+		return err
+	}
+
+	padding := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := append(plain, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, key.IV).CryptBlocks(encrypted, padded)
+
+	return os.WriteFile(segmentPath, encrypted, 0644)
+}
+
+// AES-128-CBC (PKCS7) 로 암호화된 세그먼트 복호화
+func decryptSegmentData(encrypted []byte, key *contentKey) ([]byte, error) {
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("암호화된 세그먼트 크기 오류: %d", len(encrypted))
+	}
+
+	block, err := aes.NewCipher(key.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	plain := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, key.IV).CryptBlocks(plain, encrypted)
+
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, fmt.Errorf("세그먼트 패딩 오류")
+	}
+
+	return plain[:len(plain)-padding], nil
+}
+
+// 콘텐츠 키 목록을 서버 AES 키로 암호화하여 세그먼트 구간과 함께 저장
+func saveContentKeys(userId, videoSeq string, keys []*contentKey) error {
+	dbCon, dbErr := database.InitPostgresConnection()
+
+	if dbErr != nil {
+		return dbErr
+	}
+
+	defer dbCon.Close()
+
+	// 재시도 / 재변환 시 이전 키를 지우고 새 키로 교체 - 키 일부만 남지 않도록 하나의 트랜잭션으로 처리
+	tx, txErr := dbCon.Begin()
+	if txErr != nil {
+		return txErr
+	}
+
+	defer tx.Rollback()
+
+	if _, deleteErr := tx.Exec(DeleteContentKeys, videoSeq, userId); deleteErr != nil {
+		return deleteErr
+	}
+
+	for _, key := range keys {
+		encryptedKey, encryptErr := utils.EncryptAES(key.Key, configs.GlobalConfiguration.AesKey, configs.GlobalConfiguration.AesIv)
+		if encryptErr != nil {
+			return encryptErr
+		}
+
+		_, insertErr := tx.Exec(InsertContentKey,
+			videoSeq, userId, EncryptionAES128, encryptedKey, hex.EncodeToString(key.IV), key.URI,
+			key.Index, key.FirstSegment, key.LastSegment)
+
+		if insertErr != nil {
+			return insertErr
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,228 @@
+package converter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MPEG-DASH MPD 구조체 (HLS 와 동일한 CMAF 세그먼트를 참조)
+type dashMPD struct {
+	XMLName                   xml.Name   `xml:"MPD"`
+	Xmlns                     string     `xml:"xmlns,attr"`
+	Profiles                  string     `xml:"profiles,attr"`
+	Type                      string     `xml:"type,attr"`
+	MediaPresentationDuration string     `xml:"mediaPresentationDuration,attr"`
+	MinBufferTime             string     `xml:"minBufferTime,attr"`
+	Period                    dashPeriod `xml:"Period"`
+}
+
+type dashPeriod struct {
+	ID             string              `xml:"id,attr"`
+	Start          string              `xml:"start,attr"`
+	AdaptationSets []dashAdaptationSet `xml:"AdaptationSet"`
+}
+
+type dashAdaptationSet struct {
+	ID               int                  `xml:"id,attr"`
+	ContentType      string               `xml:"contentType,attr"`
+	MimeType         string               `xml:"mimeType,attr"`
+	Lang             string               `xml:"lang,attr,omitempty"`
+	SegmentAlignment bool                 `xml:"segmentAlignment,attr"`
+	StartWithSAP     int                  `xml:"startWithSAP,attr"`
+	Representations  []dashRepresentation `xml:"Representation"`
+}
+
+type dashRepresentation struct {
+	ID                string              `xml:"id,attr"`
+	Bandwidth         int                 `xml:"bandwidth,attr"`
+	Codecs            string              `xml:"codecs,attr"`
+	Width             int                 `xml:"width,attr,omitempty"`
+	Height            int                 `xml:"height,attr,omitempty"`
+	AudioSamplingRate int                 `xml:"audioSamplingRate,attr,omitempty"`
+	SegmentTemplate   dashSegmentTemplate `xml:"SegmentTemplate"`
+}
+
+type dashSegmentTemplate struct {
+	Timescale       int                 `xml:"timescale,attr"`
+	Initialization  string              `xml:"initialization,attr"`
+	Media           string              `xml:"media,attr"`
+	StartNumber     int                 `xml:"startNumber,attr"`
+	SegmentTimeline dashSegmentTimeline `xml:"SegmentTimeline"`
+}
+
+type dashSegmentTimeline struct {
+	Segments []dashTimelineEntry `xml:"S"`
+}
+
+type dashTimelineEntry struct {
+	Time     *int64 `xml:"t,attr"`
+	Duration int64  `xml:"d,attr"`
+	Repeat   int    `xml:"r,attr,omitempty"`
+}
+
+const dashTimescale = 1000
+
+// DASH 매니페스트 파일명
+func dashManifestName(encodedFileName string) string {
+	return fmt.Sprintf("%s.mpd", encodedFileName)
+}
+
+// HLS 미디어 플레이리스트의 세그먼트 정보로 DASH SegmentTemplate 구성
+func dashTemplateFor(job *ConversionJob, encodedFileName, renditionName string) (dashSegmentTemplate, float64, error) {
+	segments, err := parseMediaPlaylist(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, renditionName)))
+	if err != nil {
+		return dashSegmentTemplate{}, 0, err
+	}
+
+	var timeline []dashTimelineEntry
+	var total float64
+	for i, seg := range segments {
+		duration := int64(math.Round(seg.Duration * dashTimescale))
+		total += seg.Duration
+
+		if n := len(timeline); n > 0 && timeline[n-1].Duration == duration {
+			timeline[n-1].Repeat++
+			continue
+		}
+
+		entry := dashTimelineEntry{Duration: duration}
+		if i == 0 {
+			start := int64(0)
+			entry.Time = &start
+		}
+		timeline = append(timeline, entry)
+	}
+
+	template := dashSegmentTemplate{
+		Timescale:       dashTimescale,
+		Initialization:  initSegmentName(encodedFileName, renditionName),
+		Media:           fmt.Sprintf("%s_%s_$Number%%03d$.m4s", encodedFileName, renditionName),
+		StartNumber:     0,
+		SegmentTimeline: dashSegmentTimeline{Segments: timeline},
+	}
+
+	return template, total, nil
+}
+
+// 마스터 플레이리스트와 같은 렌디션 구성으로 DASH 매니페스트 작성
+func writeDashManifest(job *ConversionJob, encodedFileName string) (string, error) {
+	var duration float64
+	var adaptationSets []dashAdaptationSet
+
+	// 비디오는 코덱별 AdaptationSet 으로 구성 (플레이어가 재생 가능한 코덱 세트를 선택)
+	videoSets := make(map[string]int)
+	for _, r := range job.Renditions {
+		setIndex, ok := videoSets[r.codec()]
+		if !ok {
+			setIndex = len(adaptationSets)
+			videoSets[r.codec()] = setIndex
+			adaptationSets = append(adaptationSets, dashAdaptationSet{
+				ID:               setIndex,
+				ContentType:      "video",
+				MimeType:         "video/mp4",
+				SegmentAlignment: true,
+				StartWithSAP:     1,
+			})
+		}
+
+		template, total, err := dashTemplateFor(job, encodedFileName, r.Name)
+		if err != nil {
+			return "", err
+		}
+		duration = math.Max(duration, total)
+
+		bandwidth, _ := variantBandwidth(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, r.Name)), r.MaxRate, r.VideoBitrate)
+
+		adaptationSets[setIndex].Representations = append(adaptationSets[setIndex].Representations, dashRepresentation{
+			ID:              r.Name,
+			Bandwidth:       bandwidth,
+			Codecs:          videoCodecString(r),
+			Width:           r.Width,
+			Height:          r.Height,
+			SegmentTemplate: template,
+		})
+	}
+
+	// 오디오는 원본 트랙(언어)별 AdaptationSet 으로 구성
+	audioSets := make(map[int]int)
+	for _, a := range job.AudioRenditions {
+		setIndex, ok := audioSets[a.StreamIndex]
+		if !ok {
+			setIndex = len(adaptationSets)
+			audioSets[a.StreamIndex] = setIndex
+			adaptationSets = append(adaptationSets, dashAdaptationSet{
+				ID:               setIndex,
+				ContentType:      "audio",
+				MimeType:         "audio/mp4",
+				Lang:             a.Language,
+				SegmentAlignment: true,
+				StartWithSAP:     1,
+			})
+		}
+
+		template, _, err := dashTemplateFor(job, encodedFileName, a.Name)
+		if err != nil {
+			return "", err
+		}
+
+		bandwidth, _ := variantBandwidth(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, a.Name)), a.Bitrate, a.Bitrate)
+
+		adaptationSets[setIndex].Representations = append(adaptationSets[setIndex].Representations, dashRepresentation{
+			ID:                a.Name,
+			Bandwidth:         bandwidth,
+			Codecs:            aacCodecString,
+			AudioSamplingRate: job.Profile.AudioSampleRate,
+			SegmentTemplate:   template,
+		})
+	}
+
+	mpd := dashMPD{
+		Xmlns:                     "urn:mpeg:dash:schema:mpd:2011",
+		Profiles:                  "urn:mpeg:dash:profile:isoff-live:2011",
+		Type:                      "static",
+		MediaPresentationDuration: isoDuration(duration),
+		MinBufferTime:             isoDuration(float64(job.Profile.SegmentDuration)),
+		Period: dashPeriod{
+			ID:             "0",
+			Start:          "PT0S",
+			AdaptationSets: adaptationSets,
+		},
+	}
+
+	output, err := xml.MarshalIndent(mpd, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	mpdPath := filepath.Join(job.OutputDir, dashManifestName(encodedFileName))
+	if err := os.WriteFile(mpdPath, append([]byte(xml.Header), output...), 0644); err != nil {
+		return "", err
+	}
+
+	return mpdPath, nil
+}
+
+// ISO 8601 기간 표기 (예: PT1M30.500S)
+func isoDuration(seconds float64) string {
+	var b strings.Builder
+	b.WriteString("PT")
+
+	hours := int(seconds / 3600)
+	seconds -= float64(hours * 3600)
+	minutes := int(seconds / 60)
+	seconds -= float64(minutes * 60)
+
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+	}
+	fmt.Fprintf(&b, "%.3fS", seconds)
+
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// FFmpeg / FFprobe 실패 종류
+var (
+	ErrInvalidInput     = errors.New("잘못된 입력 파일")
+	ErrUnsupportedCodec = errors.New("지원하지 않는 코덱")
+	ErrNoVideoStream    = errors.New("비디오 스트림 없음")
+	ErrDiskFull         = errors.New("디스크 공간 부족")
+	ErrKilled           = errors.New("FFmpeg 강제 종료")
+	ErrEncoderFailure   = errors.New("인코더 오류")
+
+	// 입력 파일이 아닌 파일 (출력 / 임시 파일 등) 을 찾지 못한 경우 - 서버 문제이므로 failureKinds 에 넣지 않음
+	ErrMissingFile = errors.New("파일 없음")
+
+	// 요청한 인코딩 프로파일이 없는 경우 - 요청 문제이므로 다시 보내도 같은 결과
+	ErrUnknownProfile = errors.New("알 수 없는 인코딩 프로파일")
+)
+
+// 실패 원인 코드 / 사용자 안내 메시지
+// 앞쪽부터 확인하므로 컨텍스트 종료 / 진행 없음이 프로세스 종료보다 먼저 와야 함
+var failureKinds = []struct {
+	err     error
+	code    string
+	message string
+	user    bool // 입력 파일 / 요청 문제 (재시도해도 같은 결과)
+}{
+	{ErrJobTimeout, "timeout", "변환 시간이 초과되었습니다", false},
+	{ErrJobCancelled, "cancelled", "변환이 취소되었습니다", false},
+	{ErrJobStalled, "stalled", "변환이 진행되지 않아 중단되었습니다", false},
+	{ErrInvalidInput, "invalid_input", "영상 파일이 손상되었거나 지원하지 않는 형식입니다", true},
+	{ErrUnsupportedCodec, "unsupported_codec", "지원하지 않는 코덱으로 인코딩된 파일입니다", true},
+	{ErrNoVideoStream, "no_video_stream", "영상 트랙이 없는 파일입니다", true},
+	{ErrUnknownProfile, "unknown_profile", "지원하지 않는 인코딩 프로파일입니다", true},
+	{ErrDiskFull, "disk_full", "저장 공간이 부족하여 변환하지 못했습니다", false},
+	{ErrKilled, "killed", "변환 프로세스가 중단되었습니다", false},
+	{ErrEncoderFailure, "encoder_failure", "인코딩 중 오류가 발생했습니다", false},
+}
+
+// 분류되지 않은 실패 (설정 / 파일 작성 오류 등)
+const (
+	internalFailureCode    = "internal"
+	internalFailureMessage = "변환 중 오류가 발생했습니다"
+)
+
+// FFmpeg 로그에서 실패 종류를 판단하는 문구 (소문자, 앞쪽 종류 우선)
+var ffmpegErrorPatterns = []struct {
+	kind     error
+	patterns []string
+}{
+	{ErrDiskFull, []string{"no space left on device", "disk quota exceeded"}},
+	{ErrUnsupportedCodec, []string{"no decoder found", "decoder (codec", "unknown codec", "unsupported codec", "not currently supported in container", "could not find tag for codec"}},
+	{ErrNoVideoStream, []string{"stream map '0:v", "stream specifier ':v"}},
+	{ErrInvalidInput, []string{"invalid data found when processing input", "moov atom not found", "error opening input", "could not find codec parameters", "ebml header parsing failed", "header missing"}},
+}
+
+// 파일을 찾지 못했다는 문구 - 입력 파일 경로가 함께 있을 때만 잘못된 입력으로 판단
+const missingFilePattern = "no such file or directory"
+
+// FFmpeg / FFprobe 실행 실패
+// Error() 는 원인 한 줄만 포함하고 전체 로그는 Log 에 따로 보관
+type FFmpegError struct {
+	Kind   error  // 실패 종류 (ErrInvalidInput 등)
+	Step   string // 실패한 작업 (FFmpeg, FFmpeg 포스터, FFprobe 등)
+	Detail string // 원인으로 판단한 로그 한 줄
+	Log    string // 전체 stderr 로그
+	Err    error  // 실행 오류 (종료 코드 / 시그널)
+}
+
+func (e *FFmpegError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("%s 오류 (%v): %v", e.Step, e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s 오류 (%v): %v: %s", e.Step, e.Kind, e.Err, e.Detail)
+}
+
+// errors.Is 로 실패 종류와 실행 오류 (ErrJobStalled 등) 를 모두 확인할 수 있도록 둘 다 반환
+func (e *FFmpegError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// 종료 상태와 stderr 로그로 실패 종류 판단
+// inputFile 은 "No such file or directory" 가 입력 파일에 대한 것인지 구분하는 데 사용
+func classifyFFmpegError(step, inputFile string, err error, stderr []byte) *FFmpegError {
+	output := string(stderr)
+	ffErr := &FFmpegError{Kind: ErrEncoderFailure, Step: step, Log: output, Err: err}
+
+	lines := strings.Split(output, "\n")
+	matched := false
+	for _, group := range ffmpegErrorPatterns {
+		if line, ok := findLogLine(lines, group.patterns); ok {
+			ffErr.Kind, ffErr.Detail = group.kind, line
+			matched = true
+			break
+		}
+	}
+
+	// 입력 파일이 없으면 잘못된 입력, 그 외 파일 (출력 디렉터리 / 키 정보 등) 은 서버 문제
+	if line, ok := findLogLine(lines, []string{missingFilePattern}); ok && !matched {
+		ffErr.Kind, ffErr.Detail = ErrMissingFile, line
+		if inputFile != "" && strings.Contains(line, inputFile) {
+			ffErr.Kind = ErrInvalidInput
+		}
+	}
+
+	// 시그널로 종료된 경우 (시간 초과 / 진행 없음 / 외부 종료) - 디스크 부족이 원인이면 그대로 유지
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == -1 && ffErr.Kind != ErrDiskFull {
+		ffErr.Kind, ffErr.Detail = ErrKilled, ""
+	}
+
+	if ffErr.Detail == "" && ffErr.Kind != ErrKilled {
+		ffErr.Detail = lastErrorLine(lines)
+	}
+
+	return ffErr
+}
+
+// 문구가 포함된 첫 로그 줄
+func findLogLine(lines []string, patterns []string) (string, bool) {
+	for _, line := range lines {
+		lower := strings.ToLower(line)
+		for _, pattern := range patterns {
+			if strings.Contains(lower, pattern) {
+				return strings.TrimSpace(line), true
+			}
+		}
+	}
+	return "", false
+}
+
+// 원인으로 볼 수 있는 마지막 로그 줄 ("Conversion failed!" 같은 요약 줄 제외)
+func lastErrorLine(lines []string) string {
+	last := ""
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "Conversion failed") {
+			continue
+		}
+		if strings.Contains(strings.ToLower(line), "error") {
+			return line
+		}
+		if last == "" {
+			last = line
+		}
+	}
+	return last
+}
+
+// 실패 원인 코드 (DB / Kafka 완료 메시지용)
+func FailureCode(err error) string {
+	for _, kind := range failureKinds {
+		if errors.Is(err, kind.err) {
+			return kind.code
+		}
+	}
+	return internalFailureCode
+}
+
+// 사용자에게 보여줄 짧은 실패 메시지
+func FailureMessage(err error) string {
+	for _, kind := range failureKinds {
+		if errors.Is(err, kind.err) {
+			return kind.message
+		}
+	}
+	return internalFailureMessage
+}
+
+// 입력 파일 문제로 인한 실패 여부 (false 면 서버 / 인프라 문제)
+func IsUserError(err error) bool {
+	for _, kind := range failureKinds {
+		if errors.Is(err, kind.err) {
+			return kind.user
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+// 시그널로 종료된 프로세스의 실행 오류 (ExitCode -1)
+func killedProcessError(t *testing.T) error {
+	t.Helper()
+
+	err := exec.Command("sh", "-c", "kill -9 $$").Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != -1 {
+		t.Skipf("시그널 종료 오류를 만들 수 없습니다: %v", err)
+	}
+	return err
+}
+
+func TestClassifyFFmpegError(t *testing.T) {
+	const inputFile = "/data/upload/video.mp4"
+	exitErr := errors.New("exit status 1")
+	// 시그널 종료 오류는 해당 케이스 안에서만 만들어, 만들 수 없으면 그 케이스만 건너뜀
+	killed := func(t *testing.T) error { return killedProcessError(t) }
+	stalled := func(t *testing.T) error { return fmt.Errorf("%w (2m0s): %w", ErrJobStalled, killedProcessError(t)) }
+
+	tests := []struct {
+		name       string
+		err        error
+		makeErr    func(t *testing.T) error
+		stderr     string
+		wantKind   error
+		wantCode   string
+		wantUser   bool
+		wantDetail string
+	}{
+		{
+			name:       "디스크 공간 부족",
+			err:        exitErr,
+			stderr:     "frame=  120 fps=30\nav_interleaved_write_frame(): No space left on device\nConversion failed!\n",
+			wantKind:   ErrDiskFull,
+			wantCode:   "disk_full",
+			wantDetail: "av_interleaved_write_frame(): No space left on device",
+		},
+		{
+			name:       "디코더 없음",
+			err:        exitErr,
+			stderr:     "Decoder (codec none) not found for input stream #0:0\n",
+			wantKind:   ErrUnsupportedCodec,
+			wantCode:   "unsupported_codec",
+			wantUser:   true,
+			wantDetail: "Decoder (codec none) not found for input stream #0:0",
+		},
+		{
+			name:       "비디오 스트림 없음",
+			err:        exitErr,
+			stderr:     "Stream map '0:v:0' matches no streams.\nTo ignore this, add a trailing '?' to the map.\n",
+			wantKind:   ErrNoVideoStream,
+			wantCode:   "no_video_stream",
+			wantUser:   true,
+			wantDetail: "Stream map '0:v:0' matches no streams.",
+		},
+		{
+			name:       "손상된 입력",
+			err:        exitErr,
+			stderr:     "[mov,mp4,m4a,3gp,3g2,mj2 @ 0x1] moov atom not found\n" + inputFile + ": Invalid data found when processing input\n",
+			wantKind:   ErrInvalidInput,
+			wantCode:   "invalid_input",
+			wantUser:   true,
+			wantDetail: "[mov,mp4,m4a,3gp,3g2,mj2 @ 0x1] moov atom not found",
+		},
+		{
+			name:       "입력 파일 없음",
+			err:        exitErr,
+			stderr:     inputFile + ": No such file or directory\n",
+			wantKind:   ErrInvalidInput,
+			wantCode:   "invalid_input",
+			wantUser:   true,
+			wantDetail: inputFile + ": No such file or directory",
+		},
+		{
+			name:       "출력 경로 없음은 서버 문제",
+			err:        exitErr,
+			stderr:     "[hls @ 0x1] Failed to open file '/data/hls/abc_720p_000.ts'\n/data/hls/abc_720p_000.ts: No such file or directory\n",
+			wantKind:   ErrMissingFile,
+			wantCode:   internalFailureCode,
+			wantDetail: "/data/hls/abc_720p_000.ts: No such file or directory",
+		},
+		{
+			name:       "분류되지 않은 오류는 마지막 오류 줄",
+			err:        exitErr,
+			stderr:     "[libx264 @ 0x1] broken settings\nError initializing output stream 0:0 -- Error while opening encoder\nConversion failed!\n",
+			wantKind:   ErrEncoderFailure,
+			wantCode:   "encoder_failure",
+			wantDetail: "Error initializing output stream 0:0 -- Error while opening encoder",
+		},
+		{
+			name:       "오류 문구가 없으면 마지막 줄",
+			err:        exitErr,
+			stderr:     "frame=  10 fps=0.0\nsomething went wrong\n\n",
+			wantKind:   ErrEncoderFailure,
+			wantCode:   "encoder_failure",
+			wantDetail: "something went wrong",
+		},
+		{
+			name:     "시그널 종료",
+			makeErr:  killed,
+			stderr:   "frame=  120 fps=30\n",
+			wantKind: ErrKilled,
+			wantCode: "killed",
+		},
+		{
+			name:       "시그널 종료여도 디스크 부족은 유지",
+			makeErr:    killed,
+			stderr:     "No space left on device\n",
+			wantKind:   ErrDiskFull,
+			wantCode:   "disk_full",
+			wantDetail: "No space left on device",
+		},
+		{
+			name:     "진행 없음으로 종료",
+			makeErr:  stalled,
+			stderr:   "frame=  120 fps=30\n",
+			wantKind: ErrKilled,
+			wantCode: "stalled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runErr := tt.err
+			if tt.makeErr != nil {
+				runErr = tt.makeErr(t)
+			}
+
+			got := classifyFFmpegError("FFmpeg", inputFile, runErr, []byte(tt.stderr))
+
+			if got.Kind != tt.wantKind {
+				t.Errorf("Kind = %v, want %v", got.Kind, tt.wantKind)
+			}
+			if got.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", got.Detail, tt.wantDetail)
+			}
+			if code := FailureCode(got); code != tt.wantCode {
+				t.Errorf("FailureCode() = %s, want %s", code, tt.wantCode)
+			}
+			if user := IsUserError(got); user != tt.wantUser {
+				t.Errorf("IsUserError() = %v, want %v", user, tt.wantUser)
+			}
+			if !errors.Is(got, runErr) {
+				t.Errorf("실행 오류가 errors.Is 로 확인되지 않습니다: %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 환경 변수에서 FFmpeg 경로 가져오기 또는 기본값 사용
+func ffmpegBinary() string {
+	ffmpegPath := os.Getenv("FFMPEG_PATH")
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg" // 기본값
+	}
+	return ffmpegPath
+}
+
+// 부가 작업(썸네일 등)용 FFmpeg 실행 - 본 인코딩과 같은 기준으로 진행 없음 감시
+func runFFmpegStep(ctx context.Context, job *ConversionJob, step string, args []string) error {
+	return runSideFFmpeg(ctx, job, step, args, job.stallTimeout())
+}
+
+// 출력이 늦게 나오는 부가 작업용 FFmpeg 실행 - 진행 없음 감시 없이 작업 제한 시간만 적용
+// tile / thumbnail 필터는 입력을 한참 읽은 뒤에야 프레임을 내보내므로 출력 위치와 파일 크기가 오래 그대로임
+func runUnwatchedFFmpegStep(ctx context.Context, job *ConversionJob, step string, args []string) error {
+	return runSideFFmpeg(ctx, job, step, args, 0)
+}
+
+// 부가 작업 FFmpeg 실행 후 실패 원인 분류 (stallTimeout 0 이면 감시하지 않음)
+func runSideFFmpeg(ctx context.Context, job *ConversionJob, step string, args []string, stallTimeout time.Duration) error {
+	output, err := runWatchedFFmpeg(ctx, job, "FFmpeg "+step, args, job.outputPattern(), stallTimeout, 0, nil)
+	if err != nil {
+		return classifyFFmpegError("FFmpeg "+step, job.InputFile, err, output)
+	}
+
+	return nil
+}
+
+// 렌디션별 미디어 플레이리스트 파일명
+func variantPlaylistName(encodedFileName, renditionName string) string {
+	return fmt.Sprintf("%s_%s.m3u8", encodedFileName, renditionName)
+}
+
+// 렌디션별 fMP4 초기화 세그먼트 파일명
+func initSegmentName(encodedFileName, renditionName string) string {
+	return fmt.Sprintf("%s_%s_init.mp4", encodedFileName, renditionName)
+}
+
+// 래더 전체를 한 번에 인코딩하는 FFmpeg 인자 구성
+// keyInfoPath 가 비어 있지 않으면 AES-128 로 세그먼트 암호화
+func buildLadderArgs(job *ConversionJob, profile *EncodingProfile, encodedFileName, keyInfoPath string) []string {
+	ladder := job.Renditions
+
+	args := []string{"-y", "-i", job.InputFile}
+
+	frameRate := 0.0
+	if job.MediaInfo != nil {
+		frameRate = job.MediaInfo.FrameRate
+	}
+
+	// 인코딩할 비디오 스트림을 렌디션 수만큼 분기 후 스케일링 (오디오 전용 변환 / 원본 복사 렌디션은 제외)
+	var encoded []int
+	for i, r := range ladder {
+		if r.Codec != codecCopy {
+			encoded = append(encoded, i)
+		}
+	}
+	if len(encoded) > 0 {
+		var filter strings.Builder
+		fmt.Fprintf(&filter, "[0:v]split=%d", len(encoded))
+		for _, i := range encoded {
+			fmt.Fprintf(&filter, "[v%d]", i)
+		}
+		for _, i := range encoded {
+			fmt.Fprintf(&filter, ";[v%d]scale=%d:%d,setsar=1[v%dout]", i, ladder[i].Width, ladder[i].Height, i)
+		}
+		args = append(args, "-filter_complex", filter.String())
+	}
+
+	streamMap := make([]string, 0, len(ladder)+len(job.AudioRenditions))
+	for i, r := range ladder {
+		if r.Codec == codecCopy {
+			// 원본 비디오 스트림을 그대로 복사 (세그먼트는 원본 키프레임에서 분할)
+			args = append(args,
+				"-map", fmt.Sprintf("0:v:%d", job.Source.VideoIndex),
+				fmt.Sprintf("-c:v:%d", i), "copy",
+			)
+			streamMap = append(streamMap, fmt.Sprintf("v:%d,name:%s", i, r.Name))
+			continue
+		}
+
+		encoder := r.Codec
+		if encoder == "" {
+			encoder = profile.VideoCodec
+		}
+
+		args = append(args,
+			"-map", fmt.Sprintf("[v%dout]", i),
+			fmt.Sprintf("-c:v:%d", i), encoder,
+		)
+		args = append(args, videoEncoderArgs(i, r, profile, frameRate)...)
+
+		// 렌디션 간 세그먼트 경계를 맞추기 위해 키프레임 위치 고정
+		args = append(args,
+			fmt.Sprintf("-pix_fmt:v:%d", i), "yuv420p",
+			fmt.Sprintf("-sc_threshold:v:%d", i), "0",
+			fmt.Sprintf("-force_key_frames:v:%d", i), fmt.Sprintf("expr:gte(t,n_forced*%d)", profile.GOP),
+		)
+		streamMap = append(streamMap, fmt.Sprintf("v:%d,name:%s", i, r.Name))
+	}
+
+	// 오디오는 비트레이트 / 원본 트랙별 별도 렌디션으로 분리 (HLS 오디오 그룹 / DASH 공용)
+	for i, audio := range job.AudioRenditions {
+		args = append(args,
+			"-map", fmt.Sprintf("0:a:%d", audio.StreamIndex),
+			fmt.Sprintf("-c:a:%d", i), profile.AudioCodec,
+			fmt.Sprintf("-b:a:%d", i), fmt.Sprintf("%dk", audio.Bitrate),
+		)
+		if audio.Language != "" {
+			args = append(args, fmt.Sprintf("-metadata:s:a:%d", i), "language="+audio.Language)
+		}
+		if measurement := job.loudnessFor(audio.StreamIndex); measurement != nil {
+			args = append(args, fmt.Sprintf("-filter:a:%d", i), loudnormFilter(profile.Loudness, measurement))
+		}
+		streamMap = append(streamMap, fmt.Sprintf("a:%d,name:%s", i, audio.Name))
+	}
+
+	if len(job.AudioRenditions) > 0 {
+		args = append(args, "-ac", strconv.Itoa(profile.AudioChannels), "-ar", strconv.Itoa(profile.AudioSampleRate))
+	}
+
+	args = append(args,
+		"-f", "hls",
+		"-start_number", "0",
+		"-hls_time", fmt.Sprintf("%d", profile.SegmentDuration),
+		"-hls_list_size", "0", // 모든 세그먼트를 플레이리스트에 유지
+		"-hls_playlist_type", "vod",
+	)
+
+	// 세그먼트 컨테이너: fMP4 는 렌디션별 init.mp4 + EXT-X-MAP 사용
+	segmentPattern := fmt.Sprintf("%s_%%v_%%03d.ts", encodedFileName)
+	if job.SegmentType == SegmentTypeFMP4 {
+		segmentPattern = fmt.Sprintf("%s_%%v_%%03d.m4s", encodedFileName)
+		args = append(args,
+			"-hls_segment_type", "fmp4",
+			"-hls_fmp4_init_filename", initSegmentName(encodedFileName, "%v"),
+		)
+	}
+
+	if keyInfoPath != "" {
+		args = append(args, "-hls_key_info_file", keyInfoPath)
+	}
+
+	args = append(args,
+		"-hls_segment_filename", filepath.Join(job.OutputDir, segmentPattern),
+		"-var_stream_map", strings.Join(streamMap, " "),
+		filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, "%v")),
+	)
+
+	return args
+}
+
+// 인코딩 결과로 마스터 플레이리스트 구성
+func buildMasterPlaylist(job *ConversionJob, encodedFileName string) *MasterPlaylist {
+	if job.AudioOnly {
+		return buildAudioOnlyMasterPlaylist(job, encodedFileName)
+	}
+
+	master := &MasterPlaylist{Version: 3}
+	if job.SegmentType == SegmentTypeFMP4 {
+		// EXT-X-MAP 사용 시 버전 7
+		master.Version = 7
+	}
+
+	// 오디오 그룹별 측정 비트레이트
+	audioPeak := make(map[string]int)
+	audioAverage := make(map[string]int)
+
+	for _, audio := range job.AudioRenditions {
+		uri := variantPlaylistName(encodedFileName, audio.Name)
+
+		// 그룹 내 트랙 중 가장 큰 값을 그룹 비트레이트로 사용
+		peak, average := variantBandwidth(filepath.Join(job.OutputDir, uri), audio.Bitrate, audio.Bitrate)
+		audioPeak[audio.GroupID] = max(audioPeak[audio.GroupID], peak)
+		audioAverage[audio.GroupID] = max(audioAverage[audio.GroupID], average)
+
+		master.Media = append(master.Media, MediaRendition{
+			Type:       "AUDIO",
+			GroupID:    audio.GroupID,
+			Name:       audio.Label,
+			Language:   audio.Language,
+			Default:    audio.Default,
+			Autoselect: true,
+			Channels:   strconv.Itoa(job.Profile.AudioChannels),
+			URI:        uri,
+		})
+	}
+
+	// 자막 트랙 - DEFAULT 는 하나만 허용
+	subtitleGroup := ""
+	hasDefault := false
+	for _, track := range job.Subtitles {
+		if track.Playlist == "" {
+			continue
+		}
+		subtitleGroup = subtitleGroupID
+
+		isDefault := track.Default && !hasDefault
+		hasDefault = hasDefault || isDefault
+
+		master.Media = append(master.Media, MediaRendition{
+			Type:       "SUBTITLES",
+			GroupID:    subtitleGroupID,
+			Name:       track.DisplayName(),
+			Language:   track.Language,
+			Default:    isDefault,
+			Autoselect: true,
+			URI:        filepath.Base(track.Playlist),
+		})
+	}
+
+	for _, r := range job.Renditions {
+		uri := variantPlaylistName(encodedFileName, r.Name)
+
+		peak, average := variantBandwidth(filepath.Join(job.OutputDir, uri), r.MaxRate, r.VideoBitrate)
+
+		codecs := []string{videoCodecString(r)}
+		audioGroup := ""
+		if len(job.AudioRenditions) > 0 {
+			audioGroup = audioGroupFor(job.AudioRenditions, r.AudioBitrate)
+			codecs = append(codecs, aacCodecString)
+			peak += audioPeak[audioGroup]
+			average += audioAverage[audioGroup]
+		}
+
+		master.Variants = append(master.Variants, VariantStream{
+			URI:              uri,
+			Bandwidth:        peak,
+			AverageBandwidth: average,
+			Width:            r.Width,
+			Height:           r.Height,
+			Codecs:           codecs,
+			Audio:            audioGroup,
+			Subtitles:        subtitleGroup,
+		})
+	}
+
+	// 저대역폭 회선용 오디오 전용 variant
+	if job.AudioFallbackGroup != "" {
+		if fallback, ok := audioFallbackVariant(job, encodedFileName); ok {
+			fallback.Subtitles = subtitleGroup
+			master.Variants = append(master.Variants, fallback)
+		}
+	}
+
+	return master
+}
+
+// 미디어 플레이리스트 측정 비트레이트 (측정값이 없으면 설정값 kbps 로 대체)
+func variantBandwidth(playlistPath string, nominalPeak, nominalAverage int) (int, int) {
+	peak, average, err := measureBandwidth(playlistPath)
+	if err != nil || peak == 0 {
+		return nominalPeak * 1000, nominalAverage * 1000
+	}
+	return peak, average
+}
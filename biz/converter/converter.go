@@ -1,27 +1,21 @@
 package converter
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/donghquinn/hls_converter/database"
 )
 
-// 설정 구조체
-type Config struct {
-	Port            string `json:"port"`
-	UploadDir       string `json:"upload_dir"`
-	OutputDir       string `json:"output_dir"`
-	SegmentDuration int    `json:"segment_duration"`
-}
-
 // 변환 작업 상태 구조체
 type ConversionJob struct {
 	VideoSeq    string    `json:"videoSeq"`
@@ -32,7 +26,50 @@ type ConversionJob struct {
 	CreatedAt   time.Time `json:"created_at"`
 	CompletedAt time.Time `json:"completed_at,omitempty"`
 	Error       string    `json:"error,omitempty"`
-	OutputFile  string    `json:"output_file,omitempty"` // 추가: 생성된 m3u8 파일 경로
+	OutputFile  string    `json:"output_file,omitempty"` // 추가: 생성된 마스터 m3u8 파일 경로
+
+	Progress          ConversionProgress `json:"progress"` // 인코딩 진행 상태 (CurrentProgress 로 조회)
+	progressMu        sync.RWMutex
+	progressListeners []ProgressFunc
+	outputName        string // 이번 시도의 출력 파일 이름 (실패 시 정리용)
+
+	AudioOnly bool `json:"audio_only,omitempty"` // 오디오 파일 변환 여부 (비디오 렌디션 없음)
+
+	Profile    *EncodingProfile `json:"profile,omitempty"`     // 적용된 인코딩 프로파일 (nil 이면 기본 프로파일)
+	Source     *SourceVideo     `json:"source,omitempty"`      // 원본 영상 정보
+	MediaInfo  *MediaInfo       `json:"media_info,omitempty"`  // 변환 전 확인한 원본 미디어 메타데이터
+	Renditions []Rendition      `json:"renditions,omitempty"`  // 원본에 맞춰 선택된 래더
+	EncodeMode string           `json:"encode_mode,omitempty"` // 비디오 처리 방식 (transcode / remux - 최고 렌디션 원본 복사)
+	Timeout    time.Duration    `json:"timeout,omitempty"`     // 원본 길이로 정한 최대 실행 시간
+
+	FailureReason string `json:"failure_reason,omitempty"` // 실패 원인 코드 (invalid_input / stalled / timeout 등, FailureCode 참고)
+	ErrorLog      string `json:"error_log,omitempty"`      // 실패한 FFmpeg / FFprobe 의 전체 로그 (Error 는 원인 한 줄만 포함)
+
+	AudioRenditions []AudioRendition `json:"audio_renditions,omitempty"` // 비디오와 분리된 오디오 렌디션
+	DefaultAudio    string           `json:"default_audio,omitempty"`    // 기본 오디오 트랙 (언어 코드 또는 스트림 순서)
+
+	AudioFallbackGroup string                `json:"audio_fallback_group,omitempty"` // 오디오 전용 fallback variant 가 참조하는 오디오 그룹
+	Loudness           []LoudnessMeasurement `json:"loudness,omitempty"`             // 원본 오디오 트랙별 라우드니스 측정값
+
+	SegmentType string `json:"segment_type,omitempty"` // 세그먼트 컨테이너 (비어 있으면 프로파일 설정 사용)
+	DashFile    string `json:"dash_file,omitempty"`    // 생성된 DASH mpd 파일 경로
+
+	Encryption string `json:"encryption,omitempty"` // 암호화 방식 (비어 있으면 프로파일 설정 사용)
+	KeyURI     string `json:"key_uri,omitempty"`    // EXT-X-KEY 에 기록된 (첫 번째) 키 전달 URI
+	KeyCount   int    `json:"key_count,omitempty"`  // 키 순환 시 사용된 키 개수
+
+	Subtitles        []SubtitleTrack   `json:"subtitles,omitempty"`         // 자막 트랙 (변환 후 Playlist 채워짐)
+	SkippedSubtitles []SkippedSubtitle `json:"skipped_subtitles,omitempty"` // 변환하지 못한 자막 트랙
+
+	IFramePlaylists []string `json:"iframe_playlists,omitempty"`  // 렌디션별 I-frame 플레이리스트 경로
+	SpriteFiles     []string `json:"sprite_files,omitempty"`      // 탐색바 미리보기 스프라이트 시트 경로
+	StoryboardFile  string   `json:"storyboard_file,omitempty"`   // 스프라이트 영역을 매핑하는 WebVTT 경로
+	PosterFile      string   `json:"poster_file,omitempty"`       // 가장 큰 포스터 이미지 경로
+	PosterFiles     []string `json:"poster_files,omitempty"`      // 크기별 포스터 이미지 경로
+	PreviewFile     string   `json:"preview_file,omitempty"`      // 음소거 MP4 미리보기 경로
+	PreviewWebPFile string   `json:"preview_webp_file,omitempty"` // 애니메이션 WebP 미리보기 경로
+
+	CoverArtEmbedded bool `json:"cover_art_embedded,omitempty"` // 커버 이미지를 세그먼트에 ID3 로 삽입했는지 여부
 }
 
 // 응답 구조체
@@ -42,9 +79,20 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// 작업 실행 시간 제한
+const (
+	minJobTimeout     = 10 * time.Minute // 짧은 영상도 부가 작업을 마칠 수 있는 최소 시간
+	defaultJobTimeout = 6 * time.Hour    // 원본 길이를 알 수 없을 때
+)
+
+var (
+	ErrJobTimeout   = errors.New("변환 시간 초과")
+	ErrJobCancelled = errors.New("변환 취소")
+	ErrJobStalled   = errors.New("FFmpeg 진행 없음")
+)
+
 var (
-	config Config
-	jobs   = make(map[string]*ConversionJob)
+	jobs = make(map[string]*ConversionJob)
 )
 
 // 비디오 파일 형식 검증
@@ -77,63 +125,284 @@ func EncodeFileName(filename string) string {
 }
 
 // FFmpeg를 사용하여 HLS로 변환
-func ConvertToHLS(job *ConversionJob) error {
+// ctx 가 취소되면 실행 중인 FFmpeg 프로세스 그룹을 종료하고 cancelled 상태로 끝냄
+func ConvertToHLS(ctx context.Context, job *ConversionJob) error {
 	job.Status = "processing"
 
+	profile := job.Profile
+	if profile == nil {
+		defaultProfile, profileErr := ProfileByName("")
+		if profileErr != nil {
+			return failJob(ctx, job, profileErr)
+		}
+		profile = defaultProfile
+		job.Profile = profile
+	}
+
+	// 작업 지정값이 없으면 프로파일의 세그먼트 컨테이너 사용
+	segmentType := job.SegmentType
+	if segmentType == "" {
+		segmentType = profile.SegmentType
+	}
+	segmentType, segmentErr := normalizeSegmentType(segmentType)
+	if segmentErr != nil {
+		return failJob(ctx, job, segmentErr)
+	}
+	// DASH 는 HLS 와 같은 CMAF(fMP4) 세그먼트를 공유
+	if profile.GenerateDash && segmentType != SegmentTypeFMP4 {
+		log.Printf("DASH 생성을 위해 fMP4 세그먼트 사용 (Job %s): %s -> %s", job.ID, segmentType, SegmentTypeFMP4)
+		segmentType = SegmentTypeFMP4
+	}
+	// HEVC / AV1 / VP9 렌디션은 HLS 에서 fMP4 세그먼트로만 전달 가능
+	if len(profile.ExtraCodecs) > 0 && !isAudioFile(job.InputFile) && segmentType != SegmentTypeFMP4 {
+		log.Printf("추가 코덱 렌디션을 위해 fMP4 세그먼트 사용 (Job %s): %s -> %s", job.ID, segmentType, SegmentTypeFMP4)
+		segmentType = SegmentTypeFMP4
+	}
+	job.SegmentType = segmentType
+
+	encryption := job.Encryption
+	if encryption == "" {
+		encryption = profile.Encryption
+	}
+	encryption, encryptionErr := normalizeEncryption(encryption)
+	if encryptionErr != nil {
+		return failJob(ctx, job, encryptionErr)
+	}
+	job.Encryption = encryption
+
+	// 오디오 파일은 비디오 래더 없이 AAC 비트레이트별 렌디션만 생성
+	job.AudioOnly = isAudioFile(job.InputFile)
+	if job.AudioOnly {
+		source, info, probeErr := probeAudioSource(ctx, job.InputFile)
+		if probeErr != nil {
+			return failJob(ctx, job, probeErr)
+		}
+		job.Source = source
+		job.MediaInfo = info
+		job.AudioRenditions = audioOnlyRenditions(profile.AudioOnlyBitrates, source.AudioStreams, job.DefaultAudio)
+
+		// 커버 이미지는 암호화하지 않는 fMP4 세그먼트의 timed ID3 로 삽입 (암호화 작업은 포스터로만 제공)
+		if source.CoverArtIndex >= 0 && job.Encryption == EncryptionNone && job.SegmentType != SegmentTypeFMP4 {
+			log.Printf("커버 이미지 삽입을 위해 fMP4 세그먼트 사용 (Job %s): %s -> %s", job.ID, job.SegmentType, SegmentTypeFMP4)
+			job.SegmentType = SegmentTypeFMP4
+		}
+	} else {
+		// 원본 해상도 / 회전 확인 후 래더 조정
+		source, info, probeErr := probeSource(ctx, job.InputFile)
+		if probeErr != nil {
+			return failJob(ctx, job, probeErr)
+		}
+		job.Source = source
+		job.MediaInfo = info
+		collectEmbeddedSubtitles(job)
+		job.Renditions = FitLadder(profile.Ladder, source)
+		fitH264Levels(job.Renditions, info.FrameRate)
+
+		// 추가 코덱 렌디션은 원본에 맞춘 H.264 래더 기준으로 구성
+		extra := extraCodecRenditions(job.Renditions, profile.ExtraCodecs, info.FrameRate)
+
+		// 이미 대상 조건을 충족하는 원본은 최고 렌디션만 재인코딩 없이 복사하고 낮은 렌디션은 그대로 인코딩
+		job.EncodeMode = EncodeModeTranscode
+		if profile.AllowRemux {
+			if remuxed, reason := remuxRendition(ctx, job, profile); remuxed != nil {
+				job.EncodeMode = EncodeModeRemux
+				job.Renditions[0] = *remuxed
+			} else {
+				log.Printf("재인코딩 필요 (Job %s): %s", job.ID, reason)
+			}
+		}
+
+		job.Renditions = append(job.Renditions, extra...)
+		if source.HasAudio {
+			defaultIndex := defaultAudioIndex(source.AudioStreams, job.DefaultAudio)
+			job.AudioRenditions = AudioRenditionsFor(job.Renditions, source.AudioStreams, defaultIndex)
+			addAudioFallback(job, profile.AudioFallbackBitrate, defaultIndex)
+		}
+	}
+
+	// 원본 길이에 비례한 최대 실행 시간 - 초과하면 timeout 상태로 실패
+	// 원본 확인 단계(FFprobe / 키프레임 확인)는 각각 probeTimeout 으로 제한
+	job.Timeout = jobTimeout(profile.TimeoutFactor, job.Source.Duration)
+	ctx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+
+	// API 에서 원본을 다시 확인하지 않도록 메타데이터 저장 - 실패해도 변환은 계속
+	if infoErr := saveMediaInfo(job.ID, job.VideoSeq, job.MediaInfo); infoErr != nil {
+		log.Printf("미디어 정보 저장 실패 (Job %s): %v", job.ID, infoErr)
+	}
+
+	// 라우드니스 정규화 1차 패스 - 측정값은 인코딩 시 선형 정규화에 사용
+	if profile.Loudness.Target != 0 && len(job.AudioRenditions) > 0 {
+		measureLoudness(ctx, job, profile.Loudness)
+	}
+
 	// 원본 파일명에서 인코딩된 이름 생성
 	baseName := filepath.Base(job.InputFile)
 	baseNameWithoutExt := strings.TrimSuffix(baseName, filepath.Ext(baseName))
 	encodedFileName := EncodeFileName(baseNameWithoutExt)
+	job.outputName = encodedFileName
 
-	// 출력 파일 이름 구성
+	// 출력 파일 이름 구성 - 마스터 플레이리스트
 	m3u8FileName := fmt.Sprintf("%s.m3u8", encodedFileName)
-	tsFilePattern := fmt.Sprintf("%s_%%03d.ts", encodedFileName)
 
 	// 전체 경로 설정
 	playlistPath := filepath.Join(job.OutputDir, m3u8FileName)
-	segmentPath := filepath.Join(job.OutputDir, tsFilePattern)
 
 	// 작업에 출력 파일 경로 저장
 	job.OutputFile = playlistPath
 
-	// 환경 변수에서 FFmpeg 경로 가져오기 또는 기본값 사용
-	ffmpegPath := os.Getenv("FFMPEG_PATH")
-	if ffmpegPath == "" {
-		ffmpegPath = "ffmpeg" // 기본값
-	}
-
-	log.Printf("변환 시작 (Job %s): %s -> %s", job.ID, job.InputFile, playlistPath)
-
-	// FFmpeg 명령 구성
-	cmd := exec.Command(
-		ffmpegPath,
-		"-i", job.InputFile,
-		"-profile:v", "baseline", // 호환성을 위한 프로파일
-		"-level", "3.0",
-		"-start_number", "0",
-		"-hls_time", fmt.Sprintf("%d", config.SegmentDuration),
-		"-hls_list_size", "0", // 모든 세그먼트를 플레이리스트에 유지
-		"-f", "hls",
-		"-hls_segment_filename", segmentPath,
-		playlistPath,
-	)
-
-	// FFmpeg 명령 로깅
-	log.Printf("FFmpeg 명령: %v", cmd.Args)
-
-	// 명령 실행 및 오류 처리
-	output, err := cmd.CombinedOutput()
+	log.Printf("변환 시작 (Job %s): %s -> %s (렌디션 %d개, 오디오 %d개, %s, %s)", job.ID, job.InputFile, playlistPath, len(job.Renditions), len(job.AudioRenditions), job.SegmentType, job.EncodeMode)
+
+	// 단일 키 암호화는 FFmpeg 에서 처리 - 영상별 키 생성 후 키 정보 파일 작성
+	// (키 순환은 인코딩 후 세그먼트 단위로 직접 암호화)
+	var keys []*contentKey
+	keyInfoPath := ""
+	rotating := job.Encryption == EncryptionAES128 && profile.KeyRotationSegments > 0
+	if job.Encryption == EncryptionAES128 && !rotating {
+		keyURI, uriErr := keyURIFor(job, 0, false)
+		if uriErr != nil {
+			return failJob(ctx, job, uriErr)
+		}
+
+		key, keyErr := newContentKey(keyURI)
+		if keyErr != nil {
+			return failJob(ctx, job, keyErr)
+		}
+		keys = []*contentKey{key}
+
+		keyDir, dirErr := os.MkdirTemp("", "hls_key_")
+		if dirErr != nil {
+			return failJob(ctx, job, fmt.Errorf("키 임시 디렉터리 생성 오류: %v", dirErr))
+		}
+		defer os.RemoveAll(keyDir)
+
+		keyInfoPath, dirErr = writeKeyInfoFile(keyDir, key)
+		if dirErr != nil {
+			return failJob(ctx, job, fmt.Errorf("키 정보 파일 작성 오류: %v", dirErr))
+		}
+	}
+
+	// FFmpeg 실행 - 진행 상태는 OnProgress 구독자에게 전달
+	output, err := runFFmpegWithProgress(ctx, job, buildLadderArgs(job, profile, encodedFileName, keyInfoPath), job.outputPattern(), job.stallTimeout())
 	if err != nil {
-		job.Status = "failed"
-		job.Error = fmt.Sprintf("FFmpeg 오류: %v\n%s", err, string(output))
-		job.CompletedAt = time.Now()
-		log.Printf("변환 실패 (Job %s): %v\n%s", job.ID, err, string(output))
+		return failJob(ctx, job, classifyFFmpegError("FFmpeg", job.InputFile, err, output))
+	}
 
-		go ChangeConvertStatus(job.ID, job.VideoSeq, "FAILED")
-		return err
+	// 오디오 파일의 커버 이미지를 포스터로 사용하고 세그먼트에 ID3 로 삽입 - 실패해도 변환 결과는 유지
+	if job.AudioOnly {
+		if coverErr := extractCoverArt(ctx, job, encodedFileName); coverErr != nil {
+			log.Printf("커버 이미지 추출 실패 (Job %s): %v", job.ID, coverErr)
+		} else if job.Encryption != EncryptionNone && job.PosterFile != "" {
+			// 암호화된 세그먼트는 emsg 를 제외한 비트레이트를 측정할 수 없음
+			log.Printf("암호화 세그먼트에는 커버 이미지를 삽입하지 않습니다 (Job %s)", job.ID)
+		} else if embedErr := embedCoverArt(job, encodedFileName); embedErr != nil {
+			log.Printf("커버 이미지 삽입 실패 (Job %s): %v", job.ID, embedErr)
+		}
 	}
 
-	updateErr := UpdateConvertedFileName(job.ID, job.VideoSeq, m3u8FileName)
+	// 자막 타임라인 기준 PTS 는 키 순환 암호화 전에 확인 - 확인하지 못하면 자막이 어긋나므로 제외
+	timestampMap := ""
+	if len(job.Subtitles) > 0 && !job.AudioOnly {
+		var ffmpegKey *contentKey
+		if keyInfoPath != "" {
+			ffmpegKey = keys[0]
+		}
+
+		var mapErr error
+		if timestampMap, mapErr = subtitleTimestampMap(job, encodedFileName, ffmpegKey); mapErr != nil {
+			log.Printf("자막 변환 실패 (Job %s): 타임라인 확인 오류: %v", job.ID, mapErr)
+			for i := range job.Subtitles {
+				job.skipSubtitle(&job.Subtitles[i], fmt.Sprintf("타임라인 확인 실패: %v", mapErr))
+			}
+		}
+	}
+
+	// 키 순환 암호화: N개 세그먼트마다 새 키 적용
+	if rotating {
+		rotatedKeys, rotateErr := encryptWithRotation(job, encodedFileName, profile.KeyRotationSegments)
+		if rotateErr != nil {
+			return failJob(ctx, job, fmt.Errorf("세그먼트 암호화 오류: %v", rotateErr))
+		}
+		keys = rotatedKeys
+	} else if len(keys) > 0 {
+		// 단일 키는 전체 세그먼트 구간에 적용
+		if segments, parseErr := parseMediaPlaylist(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, job.primaryRenditionName()))); parseErr == nil {
+			keys[0].LastSegment = len(segments) - 1
+		}
+	}
+	if len(keys) > 0 {
+		job.KeyURI = keys[0].URI
+		job.KeyCount = len(keys)
+	}
+
+	// 자막을 세그먼트 WebVTT 렌디션으로 변환 (비디오 세그먼트 경계 기준)
+	if !job.AudioOnly && timestampMap != "" {
+		convertSubtitles(ctx, job, encodedFileName, timestampMap)
+	}
+
+	// 모든 렌디션을 참조하는 마스터 플레이리스트 작성
+	master := buildMasterPlaylist(job, encodedFileName)
+
+	// 탐색 / 빨리감기용 I-frame 플레이리스트 (암호화된 세그먼트는 바이트 구간 참조 불가)
+	if profile.IFramePlaylists && job.Encryption != EncryptionNone && !job.AudioOnly {
+		log.Printf("암호화된 작업은 I-frame 플레이리스트를 생성하지 않습니다 (Job %s)", job.ID)
+	} else if profile.IFramePlaylists && !job.AudioOnly {
+		iframes, iframeErr := writeIFramePlaylists(job, encodedFileName)
+		if iframeErr != nil {
+			return failJob(ctx, job, fmt.Errorf("I-frame 플레이리스트 작성 오류: %v", iframeErr))
+		}
+		master.IFrames = iframes
+	}
+
+	if writeErr := writeMasterPlaylist(playlistPath, master); writeErr != nil {
+		return failJob(ctx, job, fmt.Errorf("마스터 플레이리스트 작성 오류: %v", writeErr))
+	}
+
+	// DASH 는 AES-128 전체 세그먼트 암호화를 지원하지 않으므로 생략
+	if profile.GenerateDash && job.Encryption != EncryptionNone {
+		log.Printf("암호화된 작업은 DASH 매니페스트를 생성하지 않습니다 (Job %s)", job.ID)
+	} else if profile.GenerateDash {
+		dashPath, dashErr := writeDashManifest(job, encodedFileName)
+		if dashErr != nil {
+			return failJob(ctx, job, fmt.Errorf("DASH 매니페스트 작성 오류: %v", dashErr))
+		}
+		job.DashFile = dashPath
+	}
+
+	// 키 서버가 사용할 콘텐츠 키 저장
+	if len(keys) > 0 {
+		if keyErr := saveContentKeys(job.ID, job.VideoSeq, keys); keyErr != nil {
+			return failJob(ctx, job, fmt.Errorf("암호화 키 저장 오류: %v", keyErr))
+		}
+	}
+
+	// 탐색바 미리보기 스프라이트 - 실패해도 변환 결과는 유지
+	if profile.Sprites.Interval > 0 && !job.AudioOnly {
+		if spriteErr := generateSprites(ctx, job, profile.Sprites, encodedFileName); spriteErr != nil {
+			log.Printf("스프라이트 생성 실패 (Job %s): %v", job.ID, spriteErr)
+		}
+	}
+
+	// 대표 프레임 포스터 - 실패해도 변환 결과는 유지
+	if len(profile.PosterWidths) > 0 && !job.AudioOnly {
+		if posterErr := generatePoster(ctx, job, profile.PosterWidths, encodedFileName); posterErr != nil {
+			log.Printf("포스터 생성 실패 (Job %s): %v", job.ID, posterErr)
+		}
+	}
+
+	// 목록 화면용 미리보기 클립 - 실패해도 변환 결과는 유지
+	if profile.Preview.Duration > 0 && !job.AudioOnly {
+		if previewErr := generatePreview(ctx, job, profile.Preview, encodedFileName); previewErr != nil {
+			log.Printf("미리보기 생성 실패 (Job %s): %v", job.ID, previewErr)
+		}
+	}
+
+	posterFileName := ""
+	if job.PosterFile != "" {
+		posterFileName = filepath.Base(job.PosterFile)
+	}
+
+	updateErr := UpdateConvertedFileName(job.ID, job.VideoSeq, m3u8FileName, posterFileName)
 
 	if updateErr != nil {
 		log.Printf("Error Update Db Error: %v", updateErr)
@@ -150,30 +419,88 @@ func ConvertToHLS(job *ConversionJob) error {
 	return nil
 }
 
-// 설정 로드 함수
-func LoadConfig(cfg Config) {
-	config = cfg
+// 세그먼트 개수 확인 등에 사용할 대표 렌디션 (오디오 전용 변환은 첫 오디오 렌디션)
+func (job *ConversionJob) primaryRenditionName() string {
+	if len(job.Renditions) > 0 {
+		return job.Renditions[0].Name
+	}
+	return job.AudioRenditions[0].Name
+}
+
+// 작업 실패 처리 - 상태 기록 후 DB 상태 변경
+// 컨텍스트 종료로 실패한 경우 시간 초과(timeout)와 취소(cancelled)를 구분하고
+// 입력 파일 문제(INVALID)와 서버 문제(FAILED)를 DB 상태로 구분
+// 취소된 작업은 메시지가 다시 처리되므로 DB 상태를 바꾸지 않음
+func failJob(ctx context.Context, job *ConversionJob, err error) error {
+	status, dbStatus := "failed", "FAILED"
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		status, dbStatus = "timeout", "TIMEOUT"
+		err = fmt.Errorf("%w (%s): %w", ErrJobTimeout, job.Timeout, err)
+	case errors.Is(ctx.Err(), context.Canceled):
+		status, dbStatus = "cancelled", ""
+		err = fmt.Errorf("%w: %w", ErrJobCancelled, err)
+	case IsUserError(err):
+		dbStatus = "INVALID"
+	}
+
+	job.Status = status
+	job.FailureReason = FailureCode(err)
+	job.Error = err.Error()
+	job.CompletedAt = time.Now()
+	log.Printf("변환 실패 (Job %s, %s, %s): %v", job.ID, status, job.FailureReason, err)
+
+	var ffErr *FFmpegError
+	if errors.As(err, &ffErr) {
+		job.ErrorLog = ffErr.Log
+		log.Printf("%s 로그 (Job %s):\n%s", ffErr.Step, job.ID, ffErr.Log)
+	}
 
-	// 설정값 확인 로깅
-	log.Printf("HLS 변환기 설정 로드: 세그먼트 길이 %d초", config.SegmentDuration)
+	// 재시도 / 재전송 시 이전 시도의 세그먼트가 남지 않도록 출력 정리
+	removeJobOutputs(job)
 
-	// 출력 디렉터리가 없으면 생성
-	if config.OutputDir != "" {
-		if _, err := os.Stat(config.OutputDir); os.IsNotExist(err) {
-			os.MkdirAll(config.OutputDir, 0755)
-			log.Printf("출력 디렉터리 생성: %s", config.OutputDir)
+	if dbStatus != "" {
+		go ChangeConvertStatus(job.ID, job.VideoSeq, dbStatus)
+	}
+	return err
+}
+
+// 실패한 시도의 출력 파일 삭제 (마스터 / 미디어 플레이리스트, 세그먼트, 부가 파일)
+func removeJobOutputs(job *ConversionJob) {
+	pattern := job.outputPattern()
+	if pattern == "" {
+		return
+	}
+
+	matches, _ := filepath.Glob(pattern)
+	for _, match := range matches {
+		if err := os.Remove(match); err != nil && !os.IsNotExist(err) {
+			log.Printf("출력 파일 삭제 실패 (Job %s): %v", job.ID, err)
 		}
 	}
+	if len(matches) > 0 {
+		log.Printf("실패한 변환의 출력 파일 %d개 삭제 (Job %s)", len(matches), job.ID)
+	}
+}
+
+// 원본 길이 기반 최대 실행 시간 (길이를 모르면 기본값, factor 0 이면 기본값 사용)
+func jobTimeout(factor int, duration float64) time.Duration {
+	if factor <= 0 || duration <= 0 {
+		return defaultJobTimeout
+	}
+
+	timeout := time.Duration(duration*float64(factor)) * time.Second
+	return max(timeout, minJobTimeout)
 }
 
-func UpdateConvertedFileName(userId, videoSeq, fileName string) error {
+func UpdateConvertedFileName(userId, videoSeq, fileName, posterFileName string) error {
 	dbCon, dbErr := database.InitPostgresConnection()
 
 	if dbErr != nil {
 		return dbErr
 	}
 
-	insertErr := dbCon.InsertQuery(UpdateFileName, nil, fileName, "COMPLETE", videoSeq, userId)
+	insertErr := dbCon.InsertQuery(UpdateFileName, nil, fileName, posterFileName, "COMPLETE", videoSeq, userId)
 
 	if insertErr != nil {
 		log.Printf("Error inserting converted file name: %v", insertErr)
@@ -0,0 +1,352 @@
+package converter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// I-frame 플레이리스트 항목 - 세그먼트 내 키프레임 바이트 구간
+type iFrameEntry struct {
+	URI      string
+	Duration float64
+	Offset   int64
+	Length   int64
+}
+
+// 렌디션별 I-frame 플레이리스트 파일명
+func iFramePlaylistName(encodedFileName, renditionName string) string {
+	return fmt.Sprintf("%s_%s_iframes.m3u8", encodedFileName, renditionName)
+}
+
+// 비디오 렌디션의 I-frame 전용 플레이리스트 작성
+// 모든 세그먼트는 키프레임으로 시작하므로 (force_key_frames) 세그먼트마다 첫 키프레임을 참조
+// 반환값: 최대 비트레이트 (bps)
+func writeIFramePlaylist(job *ConversionJob, encodedFileName, renditionName string) (int, error) {
+	segments, err := parseMediaPlaylist(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, renditionName)))
+	if err != nil {
+		return 0, err
+	}
+
+	fmp4 := job.SegmentType == SegmentTypeFMP4
+	entries := make([]iFrameEntry, 0, len(segments))
+	peak := 0
+	targetDuration := 0.0
+
+	for _, seg := range segments {
+		segmentPath := filepath.Join(job.OutputDir, seg.URI)
+
+		var length int64
+		if fmp4 {
+			length, err = fmp4KeyframeEnd(segmentPath)
+		} else {
+			length, err = tsKeyframeEnd(segmentPath)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%s: %v", seg.URI, err)
+		}
+
+		entries = append(entries, iFrameEntry{URI: seg.URI, Duration: seg.Duration, Offset: 0, Length: length})
+		targetDuration = math.Max(targetDuration, seg.Duration)
+
+		if seg.Duration > 0 {
+			peak = max(peak, int(float64(length*8)/seg.Duration))
+		}
+	}
+
+	var b strings.Builder
+	version := 4
+	if fmp4 {
+		version = 7
+	}
+
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#EXT-X-VERSION:%d\n", version)
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", int(math.Ceil(targetDuration)))
+	b.WriteString("#EXT-X-MEDIA-SEQUENCE:0\n")
+	b.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	b.WriteString("#EXT-X-I-FRAMES-ONLY\n")
+	if fmp4 {
+		fmt.Fprintf(&b, "#EXT-X-MAP:URI=%s\n", quotedString(initSegmentName(encodedFileName, renditionName)))
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "#EXTINF:%.6f,\n", entry.Duration)
+		fmt.Fprintf(&b, "#EXT-X-BYTERANGE:%d@%d\n", entry.Length, entry.Offset)
+		b.WriteString(entry.URI + "\n")
+	}
+	b.WriteString("#EXT-X-ENDLIST\n")
+
+	playlistPath := filepath.Join(job.OutputDir, iFramePlaylistName(encodedFileName, renditionName))
+	if err := os.WriteFile(playlistPath, []byte(b.String()), 0644); err != nil {
+		return 0, err
+	}
+
+	return peak, nil
+}
+
+// 모든 비디오 렌디션의 I-frame 플레이리스트 작성 후 마스터 플레이리스트 항목 구성
+func writeIFramePlaylists(job *ConversionJob, encodedFileName string) ([]IFrameStream, error) {
+	streams := make([]IFrameStream, 0, len(job.Renditions))
+
+	for _, r := range job.Renditions {
+		bandwidth, err := writeIFramePlaylist(job, encodedFileName, r.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		uri := iFramePlaylistName(encodedFileName, r.Name)
+		job.IFramePlaylists = append(job.IFramePlaylists, filepath.Join(job.OutputDir, uri))
+
+		streams = append(streams, IFrameStream{
+			URI:       uri,
+			Bandwidth: bandwidth,
+			Width:     r.Width,
+			Height:    r.Height,
+			Codecs:    []string{videoCodecString(r)},
+		})
+	}
+
+	return streams, nil
+}
+
+const tsPacketSize = 188
+
+// MPEG-TS 세그먼트의 첫 키프레임 끝 위치
+// 세그먼트 시작(PAT/PMT 포함)부터 다음 비디오 PES 시작 직전까지
+func tsKeyframeEnd(segmentPath string) (int64, error) {
+	data, err := os.ReadFile(segmentPath)
+	if err != nil {
+		return 0, err
+	}
+
+	foundKeyframe := false
+	var end int64 = -1
+
+	walkErr := walkVideoPES(data, func(offset int, _ []byte) bool {
+		if foundKeyframe {
+			end = int64(offset)
+			return false
+		}
+		foundKeyframe = true
+		return true
+	})
+	if walkErr != nil {
+		return 0, walkErr
+	}
+
+	if !foundKeyframe {
+		return 0, fmt.Errorf("비디오 PES 를 찾을 수 없습니다")
+	}
+	if end < 0 {
+		// 세그먼트에 프레임이 하나뿐인 경우
+		end = int64(len(data) - len(data)%tsPacketSize)
+	}
+
+	return end, nil
+}
+
+// MPEG-TS 세그먼트의 첫 비디오 PES PTS (90kHz)
+func tsFirstVideoPTS(segmentPath string) (int64, error) {
+	data, err := os.ReadFile(segmentPath)
+	if err != nil {
+		return 0, err
+	}
+
+	return firstVideoPTS(data)
+}
+
+// TS 데이터의 첫 비디오 PES PTS (90kHz)
+func firstVideoPTS(data []byte) (int64, error) {
+	pts := int64(-1)
+	walkErr := walkVideoPES(data, func(_ int, payload []byte) bool {
+		// PTS_DTS_flags 확인 후 33비트 PTS 추출
+		if len(payload) >= 14 && payload[7]&0x80 != 0 {
+			pts = int64(payload[9]>>1&0x07)<<30 |
+				int64(payload[10])<<22 |
+				int64(payload[11]>>1)<<15 |
+				int64(payload[12])<<7 |
+				int64(payload[13]>>1)
+		}
+		return false
+	})
+	if walkErr != nil {
+		return 0, walkErr
+	}
+
+	if pts < 0 {
+		return 0, fmt.Errorf("비디오 PTS 를 찾을 수 없습니다")
+	}
+
+	return pts, nil
+}
+
+// 비디오 PES 가 시작되는 TS 패킷마다 콜백 호출 (false 반환 시 중단)
+func walkVideoPES(data []byte, visit func(offset int, payload []byte) bool) error {
+	for offset := 0; offset+tsPacketSize <= len(data); offset += tsPacketSize {
+		packet := data[offset : offset+tsPacketSize]
+		if packet[0] != 0x47 {
+			return fmt.Errorf("TS 동기 바이트 오류 (offset %d)", offset)
+		}
+
+		// payload_unit_start_indicator
+		if packet[1]&0x40 == 0 {
+			continue
+		}
+
+		payloadStart := 4
+		adaptation := (packet[3] >> 4) & 0x3
+		if adaptation == 0x2 {
+			continue
+		}
+		if adaptation == 0x3 {
+			payloadStart += 1 + int(packet[4])
+		}
+		if payloadStart+4 > tsPacketSize {
+			continue
+		}
+
+		// 비디오 PES 시작 코드 (00 00 01 E0~EF)
+		payload := packet[payloadStart:]
+		isVideoPES := payload[0] == 0 && payload[1] == 0 && payload[2] == 1 && payload[3]&0xF0 == 0xE0
+		if !isVideoPES {
+			continue
+		}
+
+		if !visit(offset, payload) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// fMP4 세그먼트의 첫 샘플(키프레임) 끝 위치
+// 세그먼트 시작(styp / moof 포함)부터 mdat 의 첫 샘플 끝까지
+func fmp4KeyframeEnd(segmentPath string) (int64, error) {
+	data, err := os.ReadFile(segmentPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var offset int64
+	for offset+8 <= int64(len(data)) {
+		// 64비트 크기로 더하면 넘칠 수 있으므로 남은 길이와 비교
+		size, boxType := readBoxHeader(data[offset:])
+		if size < 8 || size > int64(len(data))-offset {
+			break
+		}
+
+		if boxType == "moof" {
+			dataOffset, sampleSize, ok := firstSampleOfFragment(data[offset : offset+size])
+			if !ok {
+				return 0, fmt.Errorf("moof 에서 샘플 정보를 찾을 수 없습니다")
+			}
+			// 데이터 오프셋이 잘못되어 파일 밖을 가리키면 바이트 구간으로 쓸 수 없음
+			end := offset + dataOffset + sampleSize
+			if dataOffset < 0 || end > int64(len(data)) {
+				return 0, fmt.Errorf("첫 샘플 위치가 세그먼트 범위를 벗어났습니다: %d", end)
+			}
+			return end, nil
+		}
+
+		offset += size
+	}
+
+	return 0, fmt.Errorf("moof 박스를 찾을 수 없습니다")
+}
+
+// ISO BMFF 박스 헤더 (크기, 타입)
+func readBoxHeader(data []byte) (int64, string) {
+	size := int64(binary.BigEndian.Uint32(data[0:4]))
+	boxType := string(data[4:8])
+
+	if size == 1 && len(data) >= 16 {
+		size = int64(binary.BigEndian.Uint64(data[8:16]))
+	}
+
+	return size, boxType
+}
+
+// moof 의 첫 번째 traf 에서 첫 샘플의 (moof 기준 데이터 오프셋, 크기)
+func firstSampleOfFragment(moof []byte) (int64, int64, bool) {
+	var offset int64 = 8
+
+	for offset+8 <= int64(len(moof)) {
+		size, boxType := readBoxHeader(moof[offset:])
+		if size < 8 || size > int64(len(moof))-offset {
+			return 0, 0, false
+		}
+
+		if boxType == "traf" {
+			return firstSampleOfTrack(moof[offset : offset+size])
+		}
+
+		offset += size
+	}
+
+	return 0, 0, false
+}
+
+func firstSampleOfTrack(traf []byte) (int64, int64, bool) {
+	var defaultSampleSize int64
+	var offset int64 = 8
+
+	for offset+8 <= int64(len(traf)) {
+		size, boxType := readBoxHeader(traf[offset:])
+		if size < 8 || size > int64(len(traf))-offset {
+			return 0, 0, false
+		}
+		box := traf[offset : offset+size]
+		if len(box) < 16 {
+			offset += size
+			continue
+		}
+
+		switch boxType {
+		case "tfhd":
+			flags := binary.BigEndian.Uint32(box[8:12]) & 0xFFFFFF
+			pos := 16 // 헤더 + version/flags + track_ID
+			if flags&0x1 != 0 {
+				pos += 8 // base_data_offset
+			}
+			if flags&0x2 != 0 {
+				pos += 4 // sample_description_index
+			}
+			if flags&0x8 != 0 {
+				pos += 4 // default_sample_duration
+			}
+			if flags&0x10 != 0 && pos+4 <= len(box) {
+				defaultSampleSize = int64(binary.BigEndian.Uint32(box[pos : pos+4]))
+			}
+		case "trun":
+			flags := binary.BigEndian.Uint32(box[8:12]) & 0xFFFFFF
+			pos := 16 // 헤더 + version/flags + sample_count
+			var dataOffset int64
+			if flags&0x1 != 0 && pos+4 <= len(box) {
+				dataOffset = int64(int32(binary.BigEndian.Uint32(box[pos : pos+4])))
+				pos += 4
+			}
+			if flags&0x4 != 0 {
+				pos += 4 // first_sample_flags
+			}
+			if flags&0x100 != 0 {
+				pos += 4 // sample_duration
+			}
+
+			sampleSize := defaultSampleSize
+			if flags&0x200 != 0 && pos+4 <= len(box) {
+				sampleSize = int64(binary.BigEndian.Uint32(box[pos : pos+4]))
+			}
+
+			return dataOffset, sampleSize, sampleSize > 0
+		}
+
+		offset += size
+	}
+
+	return 0, 0, false
+}
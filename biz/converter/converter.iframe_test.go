@@ -0,0 +1,282 @@
+package converter
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 테스트용 TS 패킷 (payload 는 188 바이트에 맞춰 0xFF 로 채움)
+func tsTestPacket(unitStart bool, adaptation []byte, payload []byte) []byte {
+	packet := make([]byte, tsPacketSize)
+	for i := range packet {
+		packet[i] = 0xFF
+	}
+
+	packet[0] = 0x47
+	packet[1] = 0x01 // PID 0x100
+	if unitStart {
+		packet[1] |= 0x40
+	}
+	packet[2] = 0x00
+
+	pos := 4
+	switch {
+	case adaptation != nil && payload == nil:
+		packet[3] = 0x20
+	case adaptation != nil:
+		packet[3] = 0x30
+	default:
+		packet[3] = 0x10
+	}
+	if adaptation != nil {
+		packet[4] = byte(len(adaptation))
+		copy(packet[5:], adaptation)
+		pos = 5 + len(adaptation)
+	}
+	copy(packet[pos:], payload)
+
+	return packet
+}
+
+// PTS 가 있는 PES 헤더 (streamID 0xE0 = 비디오, 0xC0 = 오디오)
+func pesTestHeader(streamID byte, pts int64) []byte {
+	return []byte{
+		0x00, 0x00, 0x01, streamID, 0x00, 0x00, 0x80, 0x80, 0x05,
+		byte(0x21 | (pts>>29)&0x0E),
+		byte(pts >> 22),
+		byte(0x01 | (pts>>14)&0xFE),
+		byte(pts >> 7),
+		byte(0x01 | (pts<<1)&0xFE),
+	}
+}
+
+func joinTestPackets(packets ...[]byte) []byte {
+	var data []byte
+	for _, packet := range packets {
+		data = append(data, packet...)
+	}
+	return data
+}
+
+func writeTestSegment(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTSKeyframeEnd(t *testing.T) {
+	keyframe := tsTestPacket(true, nil, pesTestHeader(0xE0, 0))
+	nextFrame := tsTestPacket(true, nil, pesTestHeader(0xE0, 3000))
+	continuation := tsTestPacket(false, nil, []byte{0x00})
+	audio := tsTestPacket(true, nil, pesTestHeader(0xC0, 0))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "다음 비디오 PES 직전까지",
+			data: joinTestPackets(keyframe, continuation, audio, nextFrame, continuation),
+			want: 3 * tsPacketSize,
+		},
+		{
+			name: "프레임 하나뿐인 세그먼트는 끝까지",
+			data: joinTestPackets(keyframe, continuation, continuation),
+			want: 3 * tsPacketSize,
+		},
+		{
+			name: "잘린 마지막 패킷은 제외",
+			data: append(joinTestPackets(keyframe, continuation), continuation[:100]...),
+			want: 2 * tsPacketSize,
+		},
+		{
+			name: "adaptation field 뒤의 PES 시작",
+			data: joinTestPackets(tsTestPacket(true, []byte{0x50, 0x00}, pesTestHeader(0xE0, 0)), tsTestPacket(true, []byte{0x00}, nil), nextFrame),
+			want: 2 * tsPacketSize,
+		},
+		{
+			name: "adaptation field 가 패킷을 모두 차지",
+			data: joinTestPackets(keyframe, tsTestPacket(true, make([]byte, 183), []byte{})),
+			want: 2 * tsPacketSize,
+		},
+		{
+			name:    "비디오 PES 없음",
+			data:    joinTestPackets(audio, continuation),
+			wantErr: true,
+		},
+		{
+			name:    "동기 바이트 오류",
+			data:    append(joinTestPackets(keyframe), make([]byte, tsPacketSize)...),
+			wantErr: true,
+		},
+		{
+			name:    "빈 세그먼트",
+			data:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tsKeyframeEnd(writeTestSegment(t, "segment.ts", tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("오류가 필요하지만 %d 반환", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("예상하지 않은 오류: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("tsKeyframeEnd() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTSFirstVideoPTS(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    int64
+		wantErr bool
+	}{
+		{"첫 비디오 PTS", joinTestPackets(tsTestPacket(true, nil, pesTestHeader(0xC0, 100)), tsTestPacket(true, nil, pesTestHeader(0xE0, 126000))), 126000, false},
+		{"33비트 PTS", joinTestPackets(tsTestPacket(true, nil, pesTestHeader(0xE0, 1<<32+5))), 1<<32 + 5, false},
+		{"PTS 없는 PES", joinTestPackets(tsTestPacket(true, nil, []byte{0x00, 0x00, 0x01, 0xE0, 0x00, 0x00, 0x80, 0x00, 0x00})), 0, true},
+		{"비디오 PES 없음", joinTestPackets(tsTestPacket(true, nil, pesTestHeader(0xC0, 0))), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tsFirstVideoPTS(writeTestSegment(t, "segment.ts", tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tsFirstVideoPTS() 오류 = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("tsFirstVideoPTS() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// ISO BMFF 박스
+func testBox(boxType string, payload ...[]byte) []byte {
+	var body []byte
+	for _, part := range payload {
+		body = append(body, part...)
+	}
+
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	box = append(box, boxType...)
+	return append(box, body...)
+}
+
+// version/flags + 필드 (32비트 값 나열)
+func testFullBox(boxType string, flags uint32, fields ...uint32) []byte {
+	body := binary.BigEndian.AppendUint32(nil, flags)
+	for _, field := range fields {
+		body = binary.BigEndian.AppendUint32(body, field)
+	}
+	return testBox(boxType, body)
+}
+
+// moof + mdat 세그먼트 - trun 의 data_offset 은 moof 시작 기준
+func testFragment(tfhd, trun []byte, mdatSize int) []byte {
+	moof := testBox("moof", testFullBox("mfhd", 0, 1), testBox("traf", tfhd, trun))
+	return append(moof, testBox("mdat", make([]byte, mdatSize))...)
+}
+
+func TestFMP4KeyframeEnd(t *testing.T) {
+	styp := testBox("styp", []byte("msdh"), make([]byte, 4))
+	tfhd := testFullBox("tfhd", 0x020000, 1)
+	// moof 크기: 8 + mfhd 16 + traf (8 + tfhd 16 + trun 28)
+	const moofSize = 8 + 16 + 8 + 16 + 28
+	trun := testFullBox("trun", 0x000201, 2, moofSize+8, 1500, 700)
+
+	// styp 뒤에서 시작하므로 오프셋에 더하면 int64 가 넘치는 크기
+	largeSize := append(append([]byte{}, styp...), binary.BigEndian.AppendUint32(nil, 1)...)
+	largeSize = append(largeSize, "moof"...)
+	largeSize = binary.BigEndian.AppendUint64(largeSize, 1<<63-1)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "styp 뒤의 첫 샘플 끝",
+			data: append(append([]byte{}, styp...), testFragment(tfhd, trun, 2200)...),
+			want: int64(len(styp)) + moofSize + 8 + 1500,
+		},
+		{
+			name: "tfhd 기본 샘플 크기 사용",
+			// tfhd 는 4바이트 늘고 trun 은 샘플 크기가 없어 8바이트 줄어듦
+			data: testFragment(testFullBox("tfhd", 0x020010, 1, 900), testFullBox("trun", 0x000001, 2, moofSize-4+8), 1800),
+			want: moofSize - 4 + 8 + 900,
+		},
+		{
+			name:    "moof 가 잘린 세그먼트",
+			data:    testFragment(tfhd, trun, 2200)[:moofSize-10],
+			wantErr: true,
+		},
+		{
+			name:    "크기 0 박스",
+			data:    append(binary.BigEndian.AppendUint32(nil, 0), "moof"...),
+			wantErr: true,
+		},
+		{
+			name:    "범위를 넘는 64비트 박스 크기",
+			data:    largeSize,
+			wantErr: true,
+		},
+		{
+			name:    "파일 밖을 가리키는 데이터 오프셋",
+			data:    testFragment(tfhd, testFullBox("trun", 0x000201, 1, 1<<20, 1500), 100),
+			wantErr: true,
+		},
+		{
+			name:    "샘플 크기 없음",
+			data:    testFragment(tfhd, testFullBox("trun", 0x000001, 1, moofSize+8), 100),
+			wantErr: true,
+		},
+		{
+			name:    "traf 없는 moof",
+			data:    append(testBox("moof", testFullBox("mfhd", 0, 1)), testBox("mdat", make([]byte, 10))...),
+			wantErr: true,
+		},
+		{
+			name:    "traf 크기가 moof 를 넘음",
+			data:    testBox("moof", binary.BigEndian.AppendUint32(nil, 4096), []byte("traf"), make([]byte, 8)),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fmp4KeyframeEnd(writeTestSegment(t, "segment.m4s", tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("오류가 필요하지만 %d 반환", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("예상하지 않은 오류: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("fmp4KeyframeEnd() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EBU R128 라우드니스 정규화 설정
+type LoudnessOptions struct {
+	Target    float64 `json:"target"`     // 목표 통합 라우드니스 (LUFS, 0 이면 정규화하지 않음)
+	TruePeak  float64 `json:"true_peak"`  // 최대 트루 피크 (dBTP)
+	LoudRange float64 `json:"loud_range"` // 목표 라우드니스 범위 (LU)
+}
+
+const (
+	defaultLoudnessTruePeak  = -1.5
+	defaultLoudnessLoudRange = 11.0
+)
+
+// 원본 오디오 트랙별 라우드니스 측정값 (1차 패스 결과)
+type LoudnessMeasurement struct {
+	StreamIndex  int     `json:"stream_index"`  // 원본 오디오 스트림 순서 (0:a:N)
+	Integrated   float64 `json:"integrated"`    // 통합 라우드니스 (LUFS)
+	TruePeak     float64 `json:"true_peak"`     // 트루 피크 (dBTP)
+	LoudRange    float64 `json:"loud_range"`    // 라우드니스 범위 (LU)
+	Threshold    float64 `json:"threshold"`     // 게이트 임계값 (LUFS)
+	TargetOffset float64 `json:"target_offset"` // loudnorm 보정 오프셋 (LU)
+	Gain         float64 `json:"gain"`          // 목표 라우드니스까지의 게인 (dB, ReplayGain 방식 재생 보정용)
+}
+
+// loudnorm print_format=json 출력 (값은 모두 문자열)
+type loudnormOutput struct {
+	InputI       string `json:"input_i"`
+	InputTP      string `json:"input_tp"`
+	InputLRA     string `json:"input_lra"`
+	InputThresh  string `json:"input_thresh"`
+	TargetOffset string `json:"target_offset"`
+}
+
+// 목표 라우드니스 설정값 파싱 (빈 값은 정규화하지 않음)
+func parseLoudnessTarget(value string) (float64, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	target, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || target > 0 || target < -70 {
+		return 0, fmt.Errorf("잘못된 목표 라우드니스: %s", value)
+	}
+
+	return target, nil
+}
+
+// 인코딩에 사용하는 원본 오디오 트랙별 라우드니스 측정
+// 측정에 실패하거나 무음 트랙이면 해당 트랙은 정규화하지 않고 계속 진행
+func measureLoudness(ctx context.Context, job *ConversionJob, options LoudnessOptions) {
+	measured := make(map[int]bool)
+
+	for _, audio := range job.AudioRenditions {
+		if measured[audio.StreamIndex] {
+			continue
+		}
+		measured[audio.StreamIndex] = true
+
+		measurement, err := measureTrackLoudness(ctx, job, options, audio.StreamIndex)
+		if err != nil {
+			log.Printf("라우드니스 측정 실패 (Job %s, 오디오 %d): %v", job.ID, audio.StreamIndex, err)
+			continue
+		}
+
+		log.Printf("라우드니스 측정 (Job %s, 오디오 %d): %.1f LUFS, %.1f dBTP, 게인 %.1f dB",
+			job.ID, audio.StreamIndex, measurement.Integrated, measurement.TruePeak, measurement.Gain)
+		job.Loudness = append(job.Loudness, *measurement)
+	}
+}
+
+// loudnorm 1차 패스 - 출력 없이 측정값만 stderr JSON 으로 수집
+func measureTrackLoudness(ctx context.Context, job *ConversionJob, options LoudnessOptions, streamIndex int) (*LoudnessMeasurement, error) {
+	args := []string{
+		"-hide_banner",
+		"-i", job.InputFile,
+		"-map", fmt.Sprintf("0:a:%d", streamIndex),
+		"-af", fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=%g:print_format=json", options.Target, options.TruePeak, options.LoudRange),
+		"-f", "null",
+		"-",
+	}
+
+	// 출력 파일이 없으므로 진행 위치로만 진행 없음 감시
+	output, err := runWatchedFFmpeg(ctx, job, "FFmpeg 라우드니스 측정", args, "", job.stallTimeout(), 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("FFmpeg 라우드니스 측정 오류: %v\n%s", err, string(output))
+	}
+
+	// 측정 결과는 출력 마지막의 JSON 블록
+	text := string(output)
+	start := strings.LastIndex(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("loudnorm 측정 결과를 찾을 수 없습니다")
+	}
+
+	var result loudnormOutput
+	if err := json.Unmarshal([]byte(text[start:end+1]), &result); err != nil {
+		return nil, fmt.Errorf("loudnorm 측정 결과 파싱 오류: %v", err)
+	}
+
+	values := make([]float64, 5)
+	for i, raw := range []string{result.InputI, result.InputTP, result.InputLRA, result.InputThresh, result.TargetOffset} {
+		value, parseErr := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		// 무음 트랙은 -inf 로 측정되므로 정규화 대상에서 제외
+		if parseErr != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, fmt.Errorf("측정할 수 없는 라우드니스 값: %q", raw)
+		}
+		values[i] = value
+	}
+
+	return &LoudnessMeasurement{
+		StreamIndex:  streamIndex,
+		Integrated:   values[0],
+		TruePeak:     values[1],
+		LoudRange:    values[2],
+		Threshold:    values[3],
+		TargetOffset: values[4],
+		Gain:         math.Round((options.Target-values[0])*100) / 100,
+	}, nil
+}
+
+// 측정값을 사용하는 loudnorm 2차 패스 필터 (선형 정규화)
+func loudnormFilter(options LoudnessOptions, m *LoudnessMeasurement) string {
+	return fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=%g:measured_I=%g:measured_TP=%g:measured_LRA=%g:measured_thresh=%g:offset=%g:linear=true",
+		options.Target, options.TruePeak, options.LoudRange,
+		m.Integrated, m.TruePeak, m.LoudRange, m.Threshold, m.TargetOffset)
+}
+
+// 원본 오디오 트랙의 라우드니스 측정값 (없으면 nil)
+func (job *ConversionJob) loudnessFor(streamIndex int) *LoudnessMeasurement {
+	for i := range job.Loudness {
+		if job.Loudness[i].StreamIndex == streamIndex {
+			return &job.Loudness[i]
+		}
+	}
+	return nil
+}
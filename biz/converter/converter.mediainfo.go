@@ -0,0 +1,136 @@
+package converter
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/donghquinn/hls_converter/database"
+)
+
+// 변환 전 FFprobe 로 확인한 원본 미디어 메타데이터
+type MediaInfo struct {
+	FormatName string  `json:"format_name"`
+	Duration   float64 `json:"duration"` // 초
+	Size       int64   `json:"size"`     // 바이트
+	Bitrate    int     `json:"bitrate"`  // 전체 비트레이트 (bps)
+
+	VideoCodec    string  `json:"video_codec,omitempty"`
+	VideoProfile  string  `json:"video_profile,omitempty"`
+	VideoLevel    int     `json:"video_level,omitempty"` // H.264 level_idc (예: 41)
+	PixelFormat   string  `json:"pixel_format,omitempty"`
+	Width         int     `json:"width,omitempty"` // 저장된 프레임 크기
+	Height        int     `json:"height,omitempty"`
+	DisplayWidth  int     `json:"display_width,omitempty"` // 회전 / 픽셀 비율 반영 크기
+	DisplayHeight int     `json:"display_height,omitempty"`
+	Rotation      int     `json:"rotation"`
+	FrameRate     float64 `json:"frame_rate,omitempty"`
+	VideoBitrate  int     `json:"video_bitrate,omitempty"` // bps
+
+	AudioCodec      string `json:"audio_codec,omitempty"`
+	AudioChannels   int    `json:"audio_channels,omitempty"`
+	AudioSampleRate int    `json:"audio_sample_rate,omitempty"` // Hz
+	AudioBitrate    int    `json:"audio_bitrate,omitempty"`     // bps
+
+	AudioTracks    int `json:"audio_tracks"`
+	SubtitleTracks int `json:"subtitle_tracks"`
+}
+
+// FFprobe 결과에서 미디어 메타데이터 구성
+// 비디오 / 오디오 정보는 변환에 사용하는 첫 번째 스트림 기준 (커버 이미지 제외)
+func mediaInfoFromProbe(probe *probeOutput, source *SourceVideo) *MediaInfo {
+	info := &MediaInfo{
+		FormatName:     probe.Format.FormatName,
+		Duration:       source.Duration,
+		Rotation:       source.Rotation,
+		AudioTracks:    len(source.AudioStreams),
+		SubtitleTracks: len(source.SubtitleStreams),
+	}
+	info.Size, _ = strconv.ParseInt(probe.Format.Size, 10, 64)
+	info.Bitrate, _ = strconv.Atoi(probe.Format.BitRate)
+
+	if source.Width > 0 && source.Height > 0 {
+		info.Width = source.Width
+		info.Height = source.Height
+		info.DisplayWidth, info.DisplayHeight = source.DisplaySize()
+	}
+
+	foundVideo, foundAudio := false, false
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			if foundVideo || stream.Disposition.AttachedPic == 1 || stream.Width == 0 || stream.Height == 0 {
+				continue
+			}
+			foundVideo = true
+
+			info.VideoCodec = stream.CodecName
+			info.VideoProfile = stream.Profile
+			info.VideoLevel = stream.Level
+			info.PixelFormat = stream.PixFmt
+			info.VideoBitrate, _ = strconv.Atoi(stream.BitRate)
+
+			// 가변 프레임레이트는 평균값 사용
+			info.FrameRate = parseFrameRate(stream.AvgFrameRate)
+			if info.FrameRate == 0 {
+				info.FrameRate = parseFrameRate(stream.RFrameRate)
+			}
+		case "audio":
+			if foundAudio {
+				continue
+			}
+			foundAudio = true
+
+			info.AudioCodec = stream.CodecName
+			info.AudioChannels = stream.Channels
+			info.AudioSampleRate, _ = strconv.Atoi(stream.SampleRate)
+			info.AudioBitrate, _ = strconv.Atoi(stream.BitRate)
+		}
+	}
+
+	return info
+}
+
+// "30000/1001" 형태의 프레임레이트 파싱 (소수점 셋째 자리까지)
+func parseFrameRate(value string) float64 {
+	parts := strings.Split(value, "/")
+
+	num, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0
+	}
+
+	den := 1.0
+	if len(parts) == 2 {
+		den, err = strconv.ParseFloat(parts[1], 64)
+		if err != nil || den == 0 {
+			return 0
+		}
+	}
+
+	return math.Round(num/den*1000) / 1000
+}
+
+// 미디어 메타데이터 저장 (재변환 시 갱신)
+func saveMediaInfo(userId, videoSeq string, info *MediaInfo) error {
+	dbCon, dbErr := database.InitPostgresConnection()
+
+	if dbErr != nil {
+		return dbErr
+	}
+
+	defer dbCon.Close()
+
+	// API 에서 전체 정보를 그대로 사용할 수 있도록 JSON 원본도 함께 저장
+	raw, marshalErr := json.Marshal(info)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	_, insertErr := dbCon.Exec(UpsertMediaInfo,
+		videoSeq, userId, info.Duration, info.Width, info.Height, info.DisplayWidth, info.DisplayHeight,
+		info.Rotation, info.VideoCodec, info.AudioCodec, info.FrameRate, info.Bitrate, string(raw))
+
+	return insertErr
+}
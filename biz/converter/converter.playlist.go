@@ -0,0 +1,213 @@
+package converter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 마스터 플레이리스트의 EXT-X-STREAM-INF 항목
+type VariantStream struct {
+	URI              string
+	Bandwidth        int // bps
+	AverageBandwidth int // bps
+	Width            int
+	Height           int
+	Codecs           []string
+	Audio            string // 오디오 그룹 ID
+	Subtitles        string // 자막 그룹 ID
+}
+
+// 마스터 플레이리스트의 EXT-X-MEDIA 항목
+type MediaRendition struct {
+	Type       string // AUDIO / SUBTITLES
+	GroupID    string
+	Name       string
+	Language   string
+	Default    bool
+	Autoselect bool
+	Channels   string
+	URI        string
+}
+
+// 마스터 플레이리스트의 EXT-X-I-FRAME-STREAM-INF 항목
+type IFrameStream struct {
+	URI       string
+	Bandwidth int // bps
+	Width     int
+	Height    int
+	Codecs    []string
+}
+
+// 마스터 플레이리스트 구조체
+type MasterPlaylist struct {
+	Version  int
+	Media    []MediaRendition
+	Variants []VariantStream
+	IFrames  []IFrameStream
+}
+
+// 마스터 플레이리스트 문자열 생성
+func (m *MasterPlaylist) String() string {
+	var b strings.Builder
+
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#EXT-X-VERSION:%d\n", m.Version)
+	b.WriteString("#EXT-X-INDEPENDENT-SEGMENTS\n")
+
+	for _, media := range m.Media {
+		attrs := []string{
+			"TYPE=" + media.Type,
+			"GROUP-ID=" + quotedString(media.GroupID),
+			"NAME=" + quotedString(media.Name),
+		}
+		if media.Language != "" {
+			attrs = append(attrs, "LANGUAGE="+quotedString(media.Language))
+		}
+		attrs = append(attrs, "DEFAULT="+yesNo(media.Default), "AUTOSELECT="+yesNo(media.Autoselect))
+		if media.Channels != "" {
+			attrs = append(attrs, "CHANNELS="+quotedString(media.Channels))
+		}
+		if media.URI != "" {
+			attrs = append(attrs, "URI="+quotedString(media.URI))
+		}
+
+		fmt.Fprintf(&b, "#EXT-X-MEDIA:%s\n", strings.Join(attrs, ","))
+	}
+
+	for _, v := range m.Variants {
+		attrs := []string{fmt.Sprintf("BANDWIDTH=%d", v.Bandwidth)}
+		if v.AverageBandwidth > 0 {
+			attrs = append(attrs, fmt.Sprintf("AVERAGE-BANDWIDTH=%d", v.AverageBandwidth))
+		}
+		if v.Width > 0 && v.Height > 0 {
+			attrs = append(attrs, fmt.Sprintf("RESOLUTION=%dx%d", v.Width, v.Height))
+		}
+		if len(v.Codecs) > 0 {
+			attrs = append(attrs, "CODECS="+quotedString(strings.Join(v.Codecs, ",")))
+		}
+		if v.Audio != "" {
+			attrs = append(attrs, "AUDIO="+quotedString(v.Audio))
+		}
+		if v.Subtitles != "" {
+			attrs = append(attrs, "SUBTITLES="+quotedString(v.Subtitles))
+		}
+
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:%s\n", strings.Join(attrs, ","))
+		b.WriteString(v.URI + "\n")
+	}
+
+	for _, iframe := range m.IFrames {
+		attrs := []string{fmt.Sprintf("BANDWIDTH=%d", iframe.Bandwidth)}
+		if iframe.Width > 0 && iframe.Height > 0 {
+			attrs = append(attrs, fmt.Sprintf("RESOLUTION=%dx%d", iframe.Width, iframe.Height))
+		}
+		if len(iframe.Codecs) > 0 {
+			attrs = append(attrs, "CODECS="+quotedString(strings.Join(iframe.Codecs, ",")))
+		}
+		attrs = append(attrs, "URI="+quotedString(iframe.URI))
+
+		fmt.Fprintf(&b, "#EXT-X-I-FRAME-STREAM-INF:%s\n", strings.Join(attrs, ","))
+	}
+
+	return b.String()
+}
+
+// 인용 문자열 속성값 (RFC 8216 4.2 - 큰따옴표 / CR / LF 는 쓸 수 없으므로 치환)
+func quotedString(value string) string {
+	return `"` + quotedStringReplacer.Replace(value) + `"`
+}
+
+var quotedStringReplacer = strings.NewReplacer(`"`, "'", "\r", " ", "\n", " ")
+
+func yesNo(value bool) string {
+	if value {
+		return "YES"
+	}
+	return "NO"
+}
+
+// 마스터 플레이리스트 파일 저장
+func writeMasterPlaylist(path string, m *MasterPlaylist) error {
+	return os.WriteFile(path, []byte(m.String()), 0644)
+}
+
+// 미디어 플레이리스트의 세그먼트 정보
+type mediaSegment struct {
+	URI      string
+	Duration float64
+}
+
+// 미디어 플레이리스트에서 세그먼트 목록 파싱
+func parseMediaPlaylist(path string) ([]mediaSegment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var segments []mediaSegment
+	var duration float64
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "#EXTINF:"):
+			value := strings.TrimPrefix(line, "#EXTINF:")
+			value = strings.SplitN(value, ",", 2)[0]
+			duration, _ = strconv.ParseFloat(value, 64)
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		default:
+			segments = append(segments, mediaSegment{URI: line, Duration: duration})
+			duration = 0
+		}
+	}
+
+	return segments, scanner.Err()
+}
+
+// 세그먼트 크기 기반 최대/평균 비트레이트 측정 (bps)
+func measureBandwidth(playlistPath string) (peak int, average int, err error) {
+	segments, err := parseMediaPlaylist(playlistPath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dir := filepath.Dir(playlistPath)
+	var totalBits, totalDuration float64
+
+	for i, seg := range segments {
+		segmentPath := filepath.Join(dir, seg.URI)
+		info, statErr := os.Stat(segmentPath)
+		if statErr != nil || seg.Duration <= 0 {
+			continue
+		}
+
+		size := info.Size()
+		// 첫 fMP4 세그먼트에 삽입한 커버 이미지 emsg 는 한 번만 받는 메타데이터이므로 제외
+		if i == 0 && strings.HasSuffix(seg.URI, ".m4s") {
+			if data, readErr := os.ReadFile(segmentPath); readErr == nil {
+				size -= leadingEventSize(data)
+			}
+		}
+
+		bits := float64(size * 8)
+		if rate := int(bits / seg.Duration); rate > peak {
+			peak = rate
+		}
+		totalBits += bits
+		totalDuration += seg.Duration
+	}
+
+	if totalDuration > 0 {
+		average = int(totalBits / totalDuration)
+	}
+
+	return peak, average, nil
+}
@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 포스터 기본 너비 목록 (큰 순서)
+var defaultPosterWidths = []int{1280, 640, 320}
+
+const (
+	// 도입부 페이드 / 로고를 피하기 위해 앞부분 건너뛰기 (영상 길이 비율, 최대 초)
+	posterSkipRatio = 0.1
+	posterSkipMax   = 30.0
+	// 후보 프레임 분석 구간 (초)
+	posterWindow = 60
+	// thumbnail 필터 배치 크기
+	posterBatch = 100
+)
+
+// 쉼표로 구분된 너비 목록 파싱 ("0" 이면 생성하지 않음)
+func parsePosterWidths(value string) ([]int, error) {
+	if strings.TrimSpace(value) == "" {
+		return defaultPosterWidths, nil
+	}
+	if strings.TrimSpace(value) == "0" {
+		return nil, nil
+	}
+
+	var widths []int
+	for _, part := range strings.Split(value, ",") {
+		width, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || width <= 0 {
+			return nil, fmt.Errorf("잘못된 포스터 너비: %s", part)
+		}
+		widths = append(widths, width)
+	}
+
+	return widths, nil
+}
+
+// 대표 프레임을 골라 여러 크기의 포스터 이미지 생성
+// 검은 화면 / 흰 화면(페이드)은 signalstats 밝기로 제외한 후 thumbnail 필터로 선택
+func generatePoster(ctx context.Context, job *ConversionJob, widths []int, encodedFileName string) error {
+	displayWidth, _ := job.Source.DisplaySize()
+
+	// 원본보다 큰 포스터는 만들지 않음
+	var targets []int
+	for _, width := range widths {
+		width = evenFloor(min(width, displayWidth))
+		if len(targets) == 0 || targets[len(targets)-1] != width {
+			targets = append(targets, width)
+		}
+	}
+
+	skip := math.Min(job.Source.Duration*posterSkipRatio, posterSkipMax)
+
+	brightnessFilter := "signalstats," +
+		"metadata=mode=select:key=lavfi.signalstats.YAVG:value=32:function=greater," +
+		"metadata=mode=select:key=lavfi.signalstats.YAVG:value=223:function=less,"
+
+	// 밝기 조건을 만족하는 프레임이 없으면 (전체가 어두운 영상 등) 조건 없이 재시도
+	for _, prefilter := range []string{brightnessFilter, ""} {
+		args, outputs := posterArgs(job, targets, encodedFileName, skip, prefilter)
+
+		if err := runUnwatchedFFmpegStep(ctx, job, "포스터", args); err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(outputs[0]); err == nil {
+			job.PosterFiles = outputs
+			job.PosterFile = outputs[0]
+			return nil
+		}
+	}
+
+	return fmt.Errorf("포스터 프레임을 선택하지 못했습니다")
+}
+
+// 포스터 추출 FFmpeg 인자 및 출력 파일 목록
+func posterArgs(job *ConversionJob, widths []int, encodedFileName string, skip float64, prefilter string) ([]string, []string) {
+	var filter strings.Builder
+	fmt.Fprintf(&filter, "[0:v]%sthumbnail=%d,split=%d", prefilter, posterBatch, len(widths))
+	for i := range widths {
+		fmt.Fprintf(&filter, "[p%d]", i)
+	}
+	for i, width := range widths {
+		fmt.Fprintf(&filter, ";[p%d]scale=%d:-2,setsar=1[p%dout]", i, width, i)
+	}
+
+	args := []string{
+		"-y",
+		"-ss", fmt.Sprintf("%.3f", skip),
+		"-t", strconv.Itoa(posterWindow),
+		"-i", job.InputFile,
+		"-filter_complex", filter.String(),
+	}
+
+	outputs := make([]string, 0, len(widths))
+	for i, width := range widths {
+		output := filepath.Join(job.OutputDir, fmt.Sprintf("%s_poster_%d.jpg", encodedFileName, width))
+		outputs = append(outputs, output)
+
+		args = append(args,
+			"-map", fmt.Sprintf("[p%dout]", i),
+			"-frames:v", "1",
+			"-q:v", "2",
+			output,
+		)
+	}
+
+	return args, outputs
+}
@@ -0,0 +1,101 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// 미리보기 클립 설정
+type PreviewOptions struct {
+	Duration float64 `json:"duration"` // 전체 길이 (초, 0 이면 생성하지 않음)
+	Width    int     `json:"width"`
+	Excerpts int     `json:"excerpts"` // 영상 전체에서 뽑을 구간 수
+}
+
+const (
+	defaultPreviewDuration = 4
+	defaultPreviewWidth    = 320
+	defaultPreviewExcerpts = 4
+	previewFrameRate       = 15
+
+	// 미리보기 길이 허용 범위 (초)
+	minPreviewDuration = 3
+	maxPreviewDuration = 6
+)
+
+// 영상 전체에 고르게 분포된 짧은 구간들을 이어 붙여
+// 음소거 MP4 / 애니메이션 WebP 미리보기 생성
+func generatePreview(ctx context.Context, job *ConversionJob, options PreviewOptions, encodedFileName string) error {
+	duration := job.Source.Duration
+	if duration <= 0 {
+		return fmt.Errorf("영상 길이를 알 수 없습니다")
+	}
+
+	excerpts := max(options.Excerpts, 1)
+	excerptLength := options.Duration / float64(excerpts)
+
+	// 영상이 미리보기보다 짧으면 앞부분 한 구간만 사용
+	if duration <= options.Duration {
+		excerpts = 1
+		excerptLength = duration
+	}
+
+	displayWidth, displayHeight := job.Source.DisplaySize()
+	width := evenFloor(min(options.Width, displayWidth))
+	height := evenFloor(int(math.Round(float64(width) * float64(displayHeight) / float64(displayWidth))))
+
+	args := []string{"-y"}
+	for i := 0; i < excerpts; i++ {
+		// 각 구간의 중심을 영상 길이의 (i + 0.5) / n 지점에 배치
+		start := duration*(float64(i)+0.5)/float64(excerpts) - excerptLength/2
+		start = math.Max(0, math.Min(start, duration-excerptLength))
+
+		args = append(args,
+			"-ss", fmt.Sprintf("%.3f", start),
+			"-t", fmt.Sprintf("%.3f", excerptLength),
+			"-i", job.InputFile,
+		)
+	}
+
+	var filter strings.Builder
+	for i := 0; i < excerpts; i++ {
+		fmt.Fprintf(&filter, "[%d:v]fps=%d,scale=%d:%d,setsar=1,setpts=PTS-STARTPTS[c%d];", i, previewFrameRate, width, height, i)
+	}
+	for i := 0; i < excerpts; i++ {
+		fmt.Fprintf(&filter, "[c%d]", i)
+	}
+	fmt.Fprintf(&filter, "concat=n=%d:v=1:a=0,split[mp4][webp]", excerpts)
+
+	mp4Path := filepath.Join(job.OutputDir, fmt.Sprintf("%s_preview.mp4", encodedFileName))
+	webpPath := filepath.Join(job.OutputDir, fmt.Sprintf("%s_preview.webp", encodedFileName))
+
+	args = append(args,
+		"-filter_complex", filter.String(),
+		"-map", "[mp4]",
+		"-an",
+		"-c:v", "libx264",
+		"-profile:v", "main",
+		"-pix_fmt", "yuv420p",
+		"-crf", "28",
+		"-movflags", "+faststart",
+		mp4Path,
+		"-map", "[webp]",
+		"-an",
+		"-c:v", "libwebp",
+		"-loop", "0",
+		"-quality", "60",
+		webpPath,
+	)
+
+	if err := runFFmpegStep(ctx, job, "미리보기", args); err != nil {
+		return err
+	}
+
+	job.PreviewFile = mp4Path
+	job.PreviewWebPFile = webpPath
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 원본 영상 정보 구조체
+type SourceVideo struct {
+	Width    int     `json:"width"`  // 저장된 프레임 너비
+	Height   int     `json:"height"` // 저장된 프레임 높이
+	Rotation int     `json:"rotation"`
+	SarNum   int     `json:"sar_num"`
+	SarDen   int     `json:"sar_den"`
+	HasAudio bool    `json:"has_audio"`
+	Duration float64 `json:"duration"` // 초
+
+	// 변환에 사용하는 비디오 스트림 순서 (0:v:N)
+	VideoIndex int `json:"video_index"`
+	// 오디오 파일의 커버 이미지 (ID3 APIC / attached picture) 비디오 스트림 순서, 없으면 -1
+	CoverArtIndex int `json:"cover_art_index"`
+
+	AudioStreams    []AudioStream    `json:"audio_streams,omitempty"`
+	SubtitleStreams []SubtitleStream `json:"subtitle_streams,omitempty"`
+}
+
+// 원본에 포함된 오디오 스트림
+type AudioStream struct {
+	Index    int    `json:"index"` // 오디오 스트림 중 순서 (0:a:N)
+	Codec    string `json:"codec"`
+	Channels int    `json:"channels"`
+	Language string `json:"language,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Default  bool   `json:"default,omitempty"`
+}
+
+// 원본에 포함된 자막 스트림
+type SubtitleStream struct {
+	Index    int    `json:"index"` // 자막 스트림 중 순서 (0:s:N)
+	Codec    string `json:"codec"`
+	Language string `json:"language,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Default  bool   `json:"default,omitempty"`
+}
+
+// ffprobe JSON 출력 중 필요한 부분
+type probeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		Size       string `json:"size"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType         string            `json:"codec_type"`
+		CodecName         string            `json:"codec_name"`
+		Profile           string            `json:"profile"`
+		Level             int               `json:"level"`
+		PixFmt            string            `json:"pix_fmt"`
+		AvgFrameRate      string            `json:"avg_frame_rate"`
+		RFrameRate        string            `json:"r_frame_rate"`
+		BitRate           string            `json:"bit_rate"`
+		SampleRate        string            `json:"sample_rate"`
+		Channels          int               `json:"channels"`
+		Width             int               `json:"width"`
+		Height            int               `json:"height"`
+		SampleAspectRatio string            `json:"sample_aspect_ratio"`
+		Tags              map[string]string `json:"tags"`
+		SideDataList      []struct {
+			SideDataType string  `json:"side_data_type"`
+			Rotation     float64 `json:"rotation"`
+		} `json:"side_data_list"`
+		Disposition struct {
+			Default     int `json:"default"`
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
+	} `json:"streams"`
+}
+
+// FFprobe 실행 시간 제한
+const probeTimeout = 2 * time.Minute
+
+// 환경 변수에서 FFprobe 경로 가져오기 또는 기본값 사용
+func ffprobeBinary() string {
+	ffprobePath := os.Getenv("FFPROBE_PATH")
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe" // 기본값
+	}
+	return ffprobePath
+}
+
+// FFprobe로 원본 영상 해상도 / 회전 / 오디오 유무 확인
+func probeSource(ctx context.Context, inputFile string) (*SourceVideo, *MediaInfo, error) {
+	source, info, err := probeMedia(ctx, inputFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if source.Width == 0 || source.Height == 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNoVideoStream, inputFile)
+	}
+
+	return source, info, nil
+}
+
+// FFprobe로 오디오 파일의 길이 / 오디오 트랙 / 커버 이미지 확인
+func probeAudioSource(ctx context.Context, inputFile string) (*SourceVideo, *MediaInfo, error) {
+	source, info, err := probeMedia(ctx, inputFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !source.HasAudio {
+		return nil, nil, fmt.Errorf("%w: 오디오 스트림이 없습니다: %s", ErrInvalidInput, inputFile)
+	}
+
+	return source, info, nil
+}
+
+// FFprobe JSON 결과를 원본 정보 / 미디어 메타데이터로 변환 (스트림 종류는 검증하지 않음)
+func probeMedia(ctx context.Context, inputFile string) (*SourceVideo, *MediaInfo, error) {
+	// 손상된 파일에서 FFprobe 가 멈추지 않도록 별도 제한
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	cmd := newCommand(ctx, ffprobeBinary(),
+		"-v", "error",
+		"-print_format", "json",
+		"-show_streams",
+		"-show_format",
+		inputFile,
+	)
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		var stderr []byte
+		if errors.As(err, &exitErr) {
+			stderr = exitErr.Stderr
+		}
+		return nil, nil, classifyFFmpegError("FFprobe", inputFile, err, stderr)
+	}
+
+	var probe probeOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, nil, fmt.Errorf("FFprobe 결과 파싱 오류: %v", err)
+	}
+
+	source := &SourceVideo{SarNum: 1, SarDen: 1, CoverArtIndex: -1}
+	source.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	foundVideo := false
+	videoIndex := -1
+
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			videoIndex++
+
+			// 커버 이미지는 영상으로 취급하지 않음
+			if stream.Disposition.AttachedPic == 1 {
+				if source.CoverArtIndex < 0 {
+					source.CoverArtIndex = videoIndex
+				}
+				continue
+			}
+
+			// 첫 번째 비디오 스트림만 사용
+			if foundVideo || stream.Width == 0 || stream.Height == 0 {
+				continue
+			}
+			foundVideo = true
+
+			source.VideoIndex = videoIndex
+			source.Width = stream.Width
+			source.Height = stream.Height
+
+			if num, den, ok := parseRatio(stream.SampleAspectRatio); ok {
+				source.SarNum, source.SarDen = num, den
+			}
+
+			// 회전 정보: 최신 FFmpeg은 Display Matrix, 구버전은 rotate 태그
+			for _, sideData := range stream.SideDataList {
+				if sideData.SideDataType == "Display Matrix" {
+					source.Rotation = int(sideData.Rotation)
+				}
+			}
+			if rotate, ok := stream.Tags["rotate"]; ok && source.Rotation == 0 {
+				source.Rotation, _ = strconv.Atoi(rotate)
+			}
+		case "audio":
+			source.HasAudio = true
+			source.AudioStreams = append(source.AudioStreams, AudioStream{
+				Index:    len(source.AudioStreams),
+				Codec:    stream.CodecName,
+				Channels: stream.Channels,
+				Language: stream.Tags["language"],
+				Title:    stream.Tags["title"],
+				Default:  stream.Disposition.Default == 1,
+			})
+		case "subtitle":
+			source.SubtitleStreams = append(source.SubtitleStreams, SubtitleStream{
+				Index:    len(source.SubtitleStreams),
+				Codec:    stream.CodecName,
+				Language: stream.Tags["language"],
+				Title:    stream.Tags["title"],
+				Default:  stream.Disposition.Default == 1,
+			})
+		}
+	}
+
+	return source, mediaInfoFromProbe(&probe, source), nil
+}
+
+// "16:9" 형태의 비율 문자열 파싱
+func parseRatio(value string) (int, int, bool) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	num, numErr := strconv.Atoi(parts[0])
+	den, denErr := strconv.Atoi(parts[1])
+	if numErr != nil || denErr != nil || num <= 0 || den <= 0 {
+		return 0, 0, false
+	}
+
+	return num, den, true
+}
+
+// 회전과 픽셀 비율을 반영한 실제 표시 해상도
+func (s *SourceVideo) DisplaySize() (int, int) {
+	width := s.Width
+	if s.SarNum > 0 && s.SarDen > 0 && s.SarNum != s.SarDen {
+		width = s.Width * s.SarNum / s.SarDen
+	}
+	height := s.Height
+
+	rotation := ((s.Rotation % 360) + 360) % 360
+	if rotation == 90 || rotation == 270 {
+		return height, width
+	}
+
+	return width, height
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package converter
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+const processWaitDelay = 10 * time.Second
+
+// 컨텍스트에 묶인 외부 명령 생성 (프로세스 그룹을 지원하지 않는 환경은 FFmpeg 프로세스만 종료)
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = processWaitDelay
+
+	return cmd
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package converter
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// 컨텍스트 종료 후 프로세스 그룹이 정리될 때까지 기다리는 시간 (이후 강제 종료)
+const processWaitDelay = 10 * time.Second
+
+// 컨텍스트에 묶인 외부 명령 생성
+// FFmpeg 가 띄운 하위 프로세스까지 함께 정리되도록 별도 프로세스 그룹으로 실행하고
+// 컨텍스트가 끝나면 그룹 전체에 SIGTERM 을 보내 정상 종료를 유도
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = processWaitDelay
+
+	return cmd
+}
@@ -0,0 +1,596 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/donghquinn/hls_converter/configs"
+)
+
+// 렌디션(화질 단계) 구조체
+type Rendition struct {
+	Name         string `json:"name"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	VideoBitrate int    `json:"video_bitrate"` // kbps
+	MaxRate      int    `json:"max_rate"`      // kbps
+	BufSize      int    `json:"buf_size"`      // kbit
+	AudioBitrate int    `json:"audio_bitrate"` // kbps
+	Profile      string `json:"profile"`       // H.264 프로파일
+	Level        string `json:"level"`
+	Codec        string `json:"codec,omitempty"` // 비디오 인코더 (비어 있으면 프로파일 VideoCodec) - 추가 코덱 / 원본 복사 렌디션만 설정
+}
+
+// 오디오 렌디션 구조체 - 비디오와 분리된 오디오 전용 스트림
+// 같은 비트레이트 그룹 안에서 원본 오디오 트랙(언어)별로 하나씩 생성
+type AudioRendition struct {
+	Name        string `json:"name"`
+	GroupID     string `json:"group_id"`
+	Bitrate     int    `json:"bitrate"`      // kbps
+	StreamIndex int    `json:"stream_index"` // 원본 오디오 스트림 순서 (0:a:N)
+	Language    string `json:"language,omitempty"`
+	Label       string `json:"label,omitempty"` // EXT-X-MEDIA NAME
+	Default     bool   `json:"default,omitempty"`
+}
+
+// 인코딩 프로파일 구조체
+type EncodingProfile struct {
+	Name            string      `json:"name"`
+	Ladder          []Rendition `json:"ladder"`
+	SegmentDuration int         `json:"segment_duration"`
+	SegmentType     string      `json:"segment_type"`  // mpegts / fmp4
+	GenerateDash    bool        `json:"generate_dash"` // fMP4 세그먼트를 공유하는 DASH 매니페스트 생성
+	Encryption      string      `json:"encryption"`    // none / aes-128
+	// 키 순환 주기 (세그먼트 개수, 0 이면 영상 전체에 단일 키)
+	KeyRotationSegments int `json:"key_rotation_segments"`
+	// 렌디션별 I-frame 전용 플레이리스트 생성
+	IFramePlaylists bool `json:"iframe_playlists"`
+	// 탐색바 미리보기용 스프라이트 시트
+	Sprites SpriteOptions `json:"sprites"`
+	// 포스터 이미지 너비 목록 (비어 있으면 생성하지 않음)
+	PosterWidths []int `json:"poster_widths"`
+	// 목록 화면용 애니메이션 미리보기
+	Preview PreviewOptions `json:"preview"`
+	// 오디오 파일 변환 시 생성할 AAC 비트레이트 목록 (kbps)
+	AudioOnlyBitrates []int `json:"audio_only_bitrates"`
+	// 비디오 마스터 플레이리스트에 추가할 오디오 전용 variant 비트레이트 (kbps, 0 이면 생성하지 않음)
+	AudioFallbackBitrate int `json:"audio_fallback_bitrate"`
+	// 2패스 loudnorm 라우드니스 정규화
+	Loudness LoudnessOptions `json:"loudness"`
+	// 원본이 대상 조건을 충족하면 재인코딩 없이 비디오 스트림 복사 (단일 렌디션)
+	AllowRemux bool `json:"allow_remux"`
+	// 최대 실행 시간 = 원본 길이 x TimeoutFactor (최소 10분)
+	TimeoutFactor int `json:"timeout_factor"`
+	// 진행 없는 FFmpeg 를 종료하기까지의 시간 (초, 0 이면 감시하지 않음)
+	StallTimeout int `json:"stall_timeout"`
+
+	// 비디오 인코더 / 속도 프리셋 (비어 있으면 인코더 기본값)
+	VideoCodec string `json:"video_codec"`
+	Preset     string `json:"preset"`
+	// 키프레임 간격 (초, 0 이면 세그먼트 길이 / 세그먼트 경계가 맞도록 세그먼트 길이의 약수여야 함)
+	GOP int `json:"gop"`
+	// 오디오 인코더 / 채널 수 / 샘플레이트 (Hz)
+	AudioCodec      string `json:"audio_codec"`
+	AudioChannels   int    `json:"audio_channels"`
+	AudioSampleRate int    `json:"audio_sample_rate"`
+	// H.264 래더와 함께 만들 추가 코덱 렌디션 (hevc / av1 / vp9 또는 인코더 이름, fMP4 세그먼트 필요)
+	ExtraCodecs []string `json:"extra_codecs"`
+}
+
+const defaultSegmentDuration = 6
+
+// 기본 비디오 / 오디오 인코딩 설정
+const (
+	defaultVideoCodec      = "libx264"
+	defaultAudioCodec      = "aac"
+	defaultAudioChannels   = 2
+	defaultAudioSampleRate = 48000
+)
+
+// 프로파일에서 사용할 수 있는 인코더 (마스터 플레이리스트 CODECS 를 만들 수 있는 것만)
+var (
+	supportedVideoCodecs = map[string]bool{"libx264": true}
+	supportedAudioCodecs = map[string]bool{"aac": true, "libfdk_aac": true}
+)
+
+// 원본 길이 대비 최대 실행 시간 배수 기본값
+const defaultTimeoutFactor = 10
+
+// 세그먼트 컨테이너 종류
+const (
+	SegmentTypeMPEGTS = "mpegts"
+	SegmentTypeFMP4   = "fmp4"
+)
+
+// 세그먼트 컨테이너 값 검증 (빈 값은 MPEG-TS)
+func normalizeSegmentType(segmentType string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(segmentType)) {
+	case "", SegmentTypeMPEGTS, "ts":
+		return SegmentTypeMPEGTS, nil
+	case SegmentTypeFMP4, "cmaf":
+		return SegmentTypeFMP4, nil
+	default:
+		return "", fmt.Errorf("지원하지 않는 세그먼트 형식: %s", segmentType)
+	}
+}
+
+// 기본 제공 렌디션 프리셋
+var renditionPresets = map[string]Rendition{
+	"1080p": {Name: "1080p", Width: 1920, Height: 1080, VideoBitrate: 5000, MaxRate: 5350, BufSize: 7500, AudioBitrate: 192, Profile: "high", Level: "4.1"},
+	"720p":  {Name: "720p", Width: 1280, Height: 720, VideoBitrate: 2800, MaxRate: 2996, BufSize: 4200, AudioBitrate: 128, Profile: "main", Level: "3.1"},
+	"480p":  {Name: "480p", Width: 854, Height: 480, VideoBitrate: 1400, MaxRate: 1498, BufSize: 2100, AudioBitrate: 128, Profile: "main", Level: "3.0"},
+	"360p":  {Name: "360p", Width: 640, Height: 360, VideoBitrate: 800, MaxRate: 856, BufSize: 1200, AudioBitrate: 96, Profile: "baseline", Level: "3.0"},
+}
+
+// 기본 렌디션 순서 (높은 화질 -> 낮은 화질)
+var defaultLadder = []string{"1080p", "720p", "480p", "360p"}
+
+// 렌디션 이름 목록으로 래더 구성
+func LadderFromNames(names []string) ([]Rendition, error) {
+	ladder := make([]Rendition, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		rendition, ok := renditionPresets[name]
+		if !ok {
+			return nil, fmt.Errorf("알 수 없는 렌디션: %s", name)
+		}
+		ladder = append(ladder, rendition)
+	}
+
+	if len(ladder) == 0 {
+		return nil, fmt.Errorf("렌디션 래더가 비어 있습니다")
+	}
+
+	return ladder, nil
+}
+
+// 환경 설정 기반 기본 인코딩 프로파일
+func DefaultProfile() (*EncodingProfile, error) {
+	names := defaultLadder
+	if configs.ConverterConfig.Renditions != "" {
+		names = strings.Split(configs.ConverterConfig.Renditions, ",")
+	}
+
+	ladder, err := LadderFromNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	segmentType, err := normalizeSegmentType(configs.ConverterConfig.SegmentType)
+	if err != nil {
+		return nil, err
+	}
+
+	encryption, err := normalizeEncryption(configs.ConverterConfig.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	keyRotationSegments, err := parseNonNegative(configs.ConverterConfig.KeyRotationSegments, 0, "키 순환 주기")
+	if err != nil {
+		return nil, err
+	}
+
+	spriteInterval, err := parseNonNegative(configs.ConverterConfig.SpriteInterval, defaultSpriteInterval, "스프라이트 간격")
+	if err != nil {
+		return nil, err
+	}
+
+	spriteFormat, err := normalizeImageFormat(configs.ConverterConfig.SpriteFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	posterWidths, err := parsePosterWidths(configs.ConverterConfig.PosterWidths)
+	if err != nil {
+		return nil, err
+	}
+
+	previewDuration, err := parseNonNegative(configs.ConverterConfig.PreviewDuration, defaultPreviewDuration, "미리보기 길이")
+	if err != nil {
+		return nil, err
+	}
+
+	previewWidth, err := parseNonNegative(configs.ConverterConfig.PreviewWidth, defaultPreviewWidth, "미리보기 너비")
+	if err != nil {
+		return nil, err
+	}
+
+	previewExcerpts, err := parseNonNegative(configs.ConverterConfig.PreviewExcerpts, defaultPreviewExcerpts, "미리보기 구간 수")
+	if err != nil {
+		return nil, err
+	}
+
+	audioOnlyBitrates, err := parseAudioOnlyBitrates(configs.ConverterConfig.AudioOnlyBitrates)
+	if err != nil {
+		return nil, err
+	}
+
+	audioFallbackBitrate, err := parseNonNegative(configs.ConverterConfig.AudioFallbackBitrate, 0, "오디오 전용 variant 비트레이트")
+	if err != nil {
+		return nil, err
+	}
+
+	loudnessTarget, err := parseLoudnessTarget(configs.ConverterConfig.LoudnessTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	timeoutFactor, err := parseNonNegative(configs.ConverterConfig.TimeoutFactor, defaultTimeoutFactor, "실행 시간 배수")
+	if err != nil {
+		return nil, err
+	}
+
+	stallTimeout, err := parseNonNegative(configs.ConverterConfig.StallTimeout, defaultStallTimeout, "진행 없음 제한 시간")
+	if err != nil {
+		return nil, err
+	}
+
+	var extraCodecs []string
+	if configs.ConverterConfig.ExtraCodecs != "" {
+		extraCodecs = strings.Split(configs.ConverterConfig.ExtraCodecs, ",")
+	}
+
+	segmentDuration, err := parseNonNegative(configs.ConverterConfig.SegmentDuration, defaultSegmentDuration, "세그먼트 길이")
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &EncodingProfile{
+		Name:            "default",
+		Ladder:          ladder,
+		SegmentDuration: segmentDuration,
+		SegmentType:     segmentType,
+		GenerateDash:    configs.ConverterConfig.GenerateDash == "true",
+		Encryption:      encryption,
+
+		KeyRotationSegments: keyRotationSegments,
+		IFramePlaylists:     configs.ConverterConfig.IFramePlaylists != "false",
+		Sprites: SpriteOptions{
+			Interval: spriteInterval,
+			Width:    defaultSpriteWidth,
+			Columns:  defaultSpriteColumns,
+			Rows:     defaultSpriteRows,
+			Format:   spriteFormat,
+		},
+		PosterWidths: posterWidths,
+		Preview: PreviewOptions{
+			Duration: float64(previewDuration),
+			Width:    previewWidth,
+			Excerpts: previewExcerpts,
+		},
+		AudioOnlyBitrates:    audioOnlyBitrates,
+		AudioFallbackBitrate: audioFallbackBitrate,
+		Loudness: LoudnessOptions{
+			Target:    loudnessTarget,
+			TruePeak:  defaultLoudnessTruePeak,
+			LoudRange: defaultLoudnessLoudRange,
+		},
+		AllowRemux:    configs.ConverterConfig.AllowRemux == "true",
+		TimeoutFactor: timeoutFactor,
+		StallTimeout:  stallTimeout,
+
+		VideoCodec:      defaultVideoCodec,
+		AudioCodec:      defaultAudioCodec,
+		AudioChannels:   defaultAudioChannels,
+		AudioSampleRate: defaultAudioSampleRate,
+		ExtraCodecs:     extraCodecs,
+	}
+
+	if err := validateProfile(profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// 프로파일 값 검증 및 생략된 값 기본값으로 채움 (설정 파일 프로파일 공용)
+func validateProfile(profile *EncodingProfile) error {
+	if strings.TrimSpace(profile.Name) == "" {
+		return fmt.Errorf("프로파일 이름이 비어 있습니다")
+	}
+	if len(profile.Ladder) == 0 {
+		return fmt.Errorf("프로파일 %s: 렌디션 래더가 비어 있습니다", profile.Name)
+	}
+
+	for i := range profile.Ladder {
+		if err := validateRendition(&profile.Ladder[i]); err != nil {
+			return fmt.Errorf("프로파일 %s: %v", profile.Name, err)
+		}
+	}
+
+	if profile.SegmentDuration <= 0 {
+		return fmt.Errorf("프로파일 %s: 잘못된 세그먼트 길이: %d", profile.Name, profile.SegmentDuration)
+	}
+	if profile.GOP == 0 {
+		profile.GOP = profile.SegmentDuration
+	}
+	if profile.GOP < 0 || profile.SegmentDuration%profile.GOP != 0 {
+		return fmt.Errorf("프로파일 %s: 키프레임 간격 %d초는 세그먼트 길이 %d초의 약수여야 합니다", profile.Name, profile.GOP, profile.SegmentDuration)
+	}
+
+	var err error
+	if profile.SegmentType, err = normalizeSegmentType(profile.SegmentType); err != nil {
+		return fmt.Errorf("프로파일 %s: %v", profile.Name, err)
+	}
+	if profile.Encryption, err = normalizeEncryption(profile.Encryption); err != nil {
+		return fmt.Errorf("프로파일 %s: %v", profile.Name, err)
+	}
+	if profile.Sprites.Format, err = normalizeImageFormat(profile.Sprites.Format); err != nil {
+		return fmt.Errorf("프로파일 %s: %v", profile.Name, err)
+	}
+
+	if profile.Preview.Duration != 0 && (profile.Preview.Duration < minPreviewDuration || profile.Preview.Duration > maxPreviewDuration) {
+		return fmt.Errorf("프로파일 %s: 미리보기 길이는 %d~%d초여야 합니다: %g", profile.Name, minPreviewDuration, maxPreviewDuration, profile.Preview.Duration)
+	}
+
+	if profile.VideoCodec == "" {
+		profile.VideoCodec = defaultVideoCodec
+	}
+	if !supportedVideoCodecs[profile.VideoCodec] {
+		return fmt.Errorf("프로파일 %s: 지원하지 않는 비디오 인코더: %s", profile.Name, profile.VideoCodec)
+	}
+
+	if profile.ExtraCodecs, err = normalizeExtraCodecs(profile.ExtraCodecs); err != nil {
+		return fmt.Errorf("프로파일 %s: %v", profile.Name, err)
+	}
+
+	if profile.AudioCodec == "" {
+		profile.AudioCodec = defaultAudioCodec
+	}
+	if !supportedAudioCodecs[profile.AudioCodec] {
+		return fmt.Errorf("프로파일 %s: 지원하지 않는 오디오 인코더: %s", profile.Name, profile.AudioCodec)
+	}
+	if profile.AudioChannels == 0 {
+		profile.AudioChannels = defaultAudioChannels
+	}
+	if profile.AudioChannels < 1 || profile.AudioChannels > 8 {
+		return fmt.Errorf("프로파일 %s: 잘못된 오디오 채널 수: %d", profile.Name, profile.AudioChannels)
+	}
+	if profile.AudioSampleRate == 0 {
+		profile.AudioSampleRate = defaultAudioSampleRate
+	}
+	if profile.AudioSampleRate < 8000 {
+		return fmt.Errorf("프로파일 %s: 잘못된 오디오 샘플레이트: %d", profile.Name, profile.AudioSampleRate)
+	}
+
+	return nil
+}
+
+// 설정 파일에서 직접 정의한 렌디션 검증 (생략한 최대 비트레이트 / 버퍼 / 프로파일 / 레벨은 기본값)
+func validateRendition(r *Rendition) error {
+	if r.Name == "" || r.Width <= 0 || r.Height <= 0 || r.VideoBitrate <= 0 || r.AudioBitrate <= 0 {
+		return fmt.Errorf("잘못된 렌디션: %+v", *r)
+	}
+	// 프로파일 / 레벨 / CODECS 는 H.264 기준이므로 래더에서 인코더를 바꾸거나 원본 복사(copy)를 지정할 수 없음
+	if r.Codec != "" {
+		return fmt.Errorf("렌디션 %s: 코덱은 지정할 수 없습니다 (video_codec / extra_codecs 사용): %s", r.Name, r.Codec)
+	}
+
+	r.Width, r.Height = evenFloor(r.Width), evenFloor(r.Height)
+	if r.MaxRate == 0 {
+		r.MaxRate = int(float64(r.VideoBitrate) * 1.07)
+	}
+	if r.BufSize == 0 {
+		r.BufSize = int(float64(r.VideoBitrate) * 1.5)
+	}
+	if r.Profile == "" {
+		r.Profile = "main"
+	}
+	if _, ok := h264ProfileRank[r.Profile]; !ok || r.Profile == "constrained baseline" {
+		return fmt.Errorf("렌디션 %s: 지원하지 않는 H.264 프로파일: %s", r.Name, r.Profile)
+	}
+	if r.Level == "" {
+		r.Level = "4.0"
+	}
+
+	return nil
+}
+
+// 0 이상의 정수 설정값 파싱 (빈 값은 기본값)
+func parseNonNegative(value string, fallback int, label string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("잘못된 %s: %s", label, value)
+	}
+
+	return parsed, nil
+}
+
+// 원본 해상도에 맞춰 래더 조정
+// - 원본보다 큰 렌디션은 만들지 않음 (업스케일 금지)
+// - 세로 영상은 박스를 회전시켜 원본 비율 유지
+// - 원본이 렌디션 사이에 있으면 원본 해상도 렌디션 하나를 추가
+// - 비트레이트는 렌디션 박스 대비 실제 화면 면적에 비례해 낮춤
+func FitLadder(ladder []Rendition, source *SourceVideo) []Rendition {
+	srcWidth, srcHeight := source.DisplaySize()
+	portrait := srcHeight > srcWidth
+
+	fitted := make([]Rendition, 0, len(ladder))
+	var native *Rendition
+
+	for _, r := range ladder {
+		boxWidth, boxHeight := r.Width, r.Height
+		if portrait {
+			boxWidth, boxHeight = r.Height, r.Width
+		}
+
+		scale := math.Min(float64(boxWidth)/float64(srcWidth), float64(boxHeight)/float64(srcHeight))
+
+		if scale >= 1 {
+			// 업스케일이 필요한 렌디션 - 원본 해상도로 낮춘 후보만 기록
+			candidate := r
+			candidate.Width, candidate.Height = evenFloor(srcWidth), evenFloor(srcHeight)
+			scaleBitrates(&candidate, float64(candidate.Width*candidate.Height)/float64(boxWidth*boxHeight))
+			candidate.Name = fmt.Sprintf("%dp", min(candidate.Width, candidate.Height))
+			native = &candidate
+			continue
+		}
+
+		fitted = append(fitted, r)
+		last := &fitted[len(fitted)-1]
+		last.Width = evenFloor(int(math.Round(float64(srcWidth) * scale)))
+		last.Height = evenFloor(int(math.Round(float64(srcHeight) * scale)))
+		// 박스보다 작은 화면 (정사각형 / 시네마 비율 등) 은 원본 해상도 렌디션과 같은 기준으로 비트레이트 축소
+		scaleBitrates(last, float64(last.Width*last.Height)/float64(boxWidth*boxHeight))
+	}
+
+	// 원본 해상도 렌디션이 기존 최고 렌디션과 겹치지 않을 때만 추가
+	if native != nil && (len(fitted) == 0 || native.Width*native.Height > fitted[0].Width*fitted[0].Height) {
+		fitted = append([]Rendition{*native}, fitted...)
+	}
+
+	return fitted
+}
+
+// 래더에서 사용하는 오디오 비트레이트별 그룹 안에 원본 오디오 트랙별 렌디션 구성
+// defaultIndex 트랙이 각 그룹의 DEFAULT 렌디션
+func AudioRenditionsFor(ladder []Rendition, tracks []AudioStream, defaultIndex int) []AudioRendition {
+	bitrates := make([]int, 0, len(ladder))
+	for _, r := range ladder {
+		bitrates = append(bitrates, r.AudioBitrate)
+	}
+
+	return audioRenditionsForBitrates(bitrates, tracks, defaultIndex)
+}
+
+// 비트레이트별 그룹 안에 오디오 트랙별 렌디션 구성 (중복 비트레이트는 한 번만)
+func audioRenditionsForBitrates(bitrates []int, tracks []AudioStream, defaultIndex int) []AudioRendition {
+	var renditions []AudioRendition
+	seen := make(map[int]bool)
+	labels := audioTrackLabels(tracks)
+
+	for _, bitrate := range bitrates {
+		if seen[bitrate] {
+			continue
+		}
+		seen[bitrate] = true
+
+		group := fmt.Sprintf("audio_%dk", bitrate)
+		for i, track := range tracks {
+			name := group
+			if len(tracks) > 1 {
+				name = fmt.Sprintf("%s_%d", group, track.Index)
+			}
+
+			renditions = append(renditions, AudioRendition{
+				Name:        name,
+				GroupID:     group,
+				Bitrate:     bitrate,
+				StreamIndex: track.Index,
+				Language:    normalizeLanguage(track.Language),
+				Label:       labels[i],
+				Default:     track.Index == defaultIndex,
+			})
+		}
+	}
+
+	return renditions
+}
+
+// 오디오 트랙별 표시 이름 (같은 그룹 안에서 NAME 이 겹치지 않도록 번호 추가)
+func audioTrackLabels(tracks []AudioStream) []string {
+	labels := make([]string, len(tracks))
+	count := make(map[string]int)
+
+	for i, track := range tracks {
+		label := trackDisplayName(track.Title, normalizeLanguage(track.Language))
+		count[label]++
+		if count[label] > 1 {
+			label = fmt.Sprintf("%s (%d)", label, count[label])
+		}
+		labels[i] = label
+	}
+
+	return labels
+}
+
+// 기본 오디오 트랙 선택
+// 요청 값이 숫자면 스트림 순서, 아니면 언어 코드로 찾고
+// 없으면 원본의 default 표시 트랙, 그것도 없으면 첫 번째 트랙
+func defaultAudioIndex(tracks []AudioStream, preferred string) int {
+	preferred = strings.TrimSpace(preferred)
+
+	if preferred != "" {
+		if index, err := strconv.Atoi(preferred); err == nil {
+			for _, track := range tracks {
+				if track.Index == index {
+					return index
+				}
+			}
+		}
+
+		language := normalizeLanguage(preferred)
+		for _, track := range tracks {
+			if normalizeLanguage(track.Language) == language {
+				return track.Index
+			}
+		}
+	}
+
+	for _, track := range tracks {
+		if track.Default {
+			return track.Index
+		}
+	}
+
+	if len(tracks) > 0 {
+		return tracks[0].Index
+	}
+	return 0
+}
+
+// 비디오 렌디션이 참조할 오디오 그룹 ID
+func audioGroupFor(audioRenditions []AudioRendition, bitrate int) string {
+	for _, audio := range audioRenditions {
+		if audio.Bitrate == bitrate {
+			return audio.GroupID
+		}
+	}
+	return audioRenditions[0].GroupID
+}
+
+// 해상도 축소 비율에 맞춰 비트레이트 조정
+func scaleBitrates(r *Rendition, ratio float64) {
+	if ratio >= 1 {
+		return
+	}
+	r.VideoBitrate = int(float64(r.VideoBitrate) * ratio)
+	r.MaxRate = int(float64(r.MaxRate) * ratio)
+	r.BufSize = int(float64(r.BufSize) * ratio)
+}
+
+// 인코더 요구사항에 맞게 짝수로 내림
+func evenFloor(value int) int {
+	if value < 2 {
+		return 2
+	}
+	return value - value%2
+}
+
+// RFC 6381 CODECS 문자열 - H.264 (avc1.PPCCLL)
+func h264CodecString(profile, level string) string {
+	profileIdc, constraint := "42", "E0"
+	switch profile {
+	case "main":
+		profileIdc, constraint = "4D", "40"
+	case "high":
+		profileIdc, constraint = "64", "00"
+	}
+
+	return fmt.Sprintf("avc1.%s%s%02X", profileIdc, constraint, h264LevelIdc(level))
+}
+
+// AAC-LC CODECS 문자열
+const aacCodecString = "mp4a.40.2"
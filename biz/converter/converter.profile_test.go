@@ -0,0 +1,127 @@
+package converter
+
+import "testing"
+
+// 기본 래더 (1080p / 720p / 480p / 360p)
+func testDefaultLadder() []Rendition {
+	ladder := make([]Rendition, 0, len(defaultLadder))
+	for _, name := range defaultLadder {
+		ladder = append(ladder, renditionPresets[name])
+	}
+	return ladder
+}
+
+func TestFitLadder(t *testing.T) {
+	type rung struct {
+		name          string
+		width, height int
+		videoBitrate  int
+	}
+
+	landscape := []rung{
+		{"1080p", 1920, 1080, 5000},
+		{"720p", 1280, 720, 2800},
+		{"480p", 852, 480, 1396},
+		{"360p", 640, 360, 800},
+	}
+	portrait := []rung{
+		{"1080p", 1080, 1920, 5000},
+		{"720p", 720, 1280, 2800},
+		{"480p", 480, 852, 1396},
+		{"360p", 360, 640, 800},
+	}
+
+	tests := []struct {
+		name   string
+		source SourceVideo
+		want   []rung
+	}{
+		{"16:9 가로 영상", SourceVideo{Width: 1920, Height: 1080}, landscape},
+		{"90도 회전 메타데이터", SourceVideo{Width: 1920, Height: 1080, Rotation: 90}, portrait},
+		{"-90도 회전 메타데이터", SourceVideo{Width: 1920, Height: 1080, Rotation: -90}, portrait},
+		{"세로로 저장된 영상", SourceVideo{Width: 1080, Height: 1920}, portrait},
+		{"비정사각 픽셀 (SAR 4:3)", SourceVideo{Width: 1440, Height: 1080, SarNum: 4, SarDen: 3}, landscape},
+		{
+			"정사각형 영상은 면적에 비례해 비트레이트 축소",
+			SourceVideo{Width: 1000, Height: 1000},
+			[]rung{
+				{"1000p", 1000, 1000, 2411},
+				{"720p", 720, 720, 1575},
+				{"480p", 480, 480, 786},
+				{"360p", 360, 360, 450},
+			},
+		},
+		{
+			"시네마 비율 영상",
+			SourceVideo{Width: 2560, Height: 1080},
+			[]rung{
+				{"1080p", 1920, 810, 3750},
+				{"720p", 1280, 540, 2100},
+				{"480p", 854, 360, 1050},
+				{"360p", 640, 270, 600},
+			},
+		},
+		{
+			"렌디션 사이 해상도는 원본 렌디션 추가",
+			SourceVideo{Width: 1600, Height: 900},
+			[]rung{
+				{"900p", 1600, 900, 3472},
+				{"720p", 1280, 720, 2800},
+				{"480p", 852, 480, 1396},
+				{"360p", 640, 360, 800},
+			},
+		},
+		{"가장 낮은 렌디션보다 작지 않은 원본은 업스케일하지 않음", SourceVideo{Width: 640, Height: 360}, []rung{{"360p", 640, 360, 800}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FitLadder(testDefaultLadder(), &tt.source)
+			if len(got) != len(tt.want) {
+				t.Fatalf("렌디션 %d개, want %d개: %+v", len(got), len(tt.want), got)
+			}
+
+			for i, want := range tt.want {
+				r := got[i]
+				if r.Name != want.name || r.Width != want.width || r.Height != want.height || r.VideoBitrate != want.videoBitrate {
+					t.Errorf("렌디션 %d = %s %dx%d %dk, want %s %dx%d %dk",
+						i, r.Name, r.Width, r.Height, r.VideoBitrate, want.name, want.width, want.height, want.videoBitrate)
+				}
+				if r.Width%2 != 0 || r.Height%2 != 0 {
+					t.Errorf("렌디션 %s 크기가 짝수가 아님: %dx%d", r.Name, r.Width, r.Height)
+				}
+				// BANDWIDTH 가 화질 순서와 같도록 비트레이트는 단조 감소
+				if i > 0 && r.VideoBitrate >= got[i-1].VideoBitrate {
+					t.Errorf("렌디션 %s 비트레이트 %dk 가 상위 렌디션 %dk 이상", r.Name, r.VideoBitrate, got[i-1].VideoBitrate)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRendition(t *testing.T) {
+	tests := []struct {
+		name      string
+		rendition Rendition
+		wantErr   bool
+	}{
+		{"기본값 채움", Rendition{Name: "720p", Width: 1280, Height: 720, VideoBitrate: 2800, AudioBitrate: 128}, false},
+		{"크기 없음", Rendition{Name: "720p", VideoBitrate: 2800, AudioBitrate: 128}, true},
+		{"H.264 가 아닌 프로파일", Rendition{Name: "720p", Width: 1280, Height: 720, VideoBitrate: 2800, AudioBitrate: 128, Profile: "main10"}, true},
+		{"인코더 지정 불가", Rendition{Name: "720p", Width: 1280, Height: 720, VideoBitrate: 2800, AudioBitrate: 128, Codec: "libx265"}, true},
+		{"원본 복사 지정 불가", Rendition{Name: "720p", Width: 1280, Height: 720, VideoBitrate: 2800, AudioBitrate: 128, Codec: codecCopy}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.rendition
+			err := validateRendition(&r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRendition() 오류 = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (r.MaxRate == 0 || r.BufSize == 0 || r.Profile == "" || r.Level == "") {
+				t.Errorf("기본값이 채워지지 않았습니다: %+v", r)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/donghquinn/hls_converter/configs"
+)
+
+// 프로파일 설정 파일 (JSON)
+// 각 프로파일은 환경 설정 기본 프로파일 위에 지정한 값만 덮어씀
+//
+//	{
+//	  "default": "web",
+//	  "profiles": [
+//	    {"name": "web", "renditions": ["1080p", "720p", "480p"], "segment_type": "fmp4", "extra_codecs": ["hevc", "av1"]},
+//	    {"name": "mobile", "ladder": [{"name": "540p", "width": 960, "height": 540, "video_bitrate": 1800, "audio_bitrate": 128}], "gop": 2}
+//	  ]
+//	}
+type profileFile struct {
+	Default  string            `json:"default"`
+	Profiles []json.RawMessage `json:"profiles"`
+}
+
+// 렌디션 프리셋 이름으로 래더 지정 (ladder 보다 우선)
+type profileRenditionNames struct {
+	Renditions []string `json:"renditions"`
+}
+
+// 이름별 인코딩 프로파일 (LoadProfiles 로 설정)
+var (
+	profilesMu         sync.RWMutex
+	profiles           map[string]*EncodingProfile
+	defaultProfileName string
+)
+
+// 환경 설정 기본 프로파일과 HLS_PROFILES_FILE 의 프로파일 로드
+// 설정 파일에 같은 이름이 있으면 환경 설정 프로파일을 대체
+func LoadProfiles() error {
+	base, err := DefaultProfile()
+	if err != nil {
+		return err
+	}
+
+	loaded := map[string]*EncodingProfile{base.Name: base}
+	defaultName := base.Name
+
+	if path := configs.ConverterConfig.ProfilesFile; path != "" {
+		fileProfiles, fileDefault, fileErr := loadProfileFile(path)
+		if fileErr != nil {
+			return fmt.Errorf("프로파일 설정 파일 오류 (%s): %v", path, fileErr)
+		}
+
+		for _, profile := range fileProfiles {
+			loaded[profile.Name] = profile
+		}
+		if fileDefault != "" {
+			if _, ok := loaded[fileDefault]; !ok {
+				return fmt.Errorf("기본 프로파일을 찾을 수 없습니다: %s", fileDefault)
+			}
+			defaultName = fileDefault
+		}
+	}
+
+	profilesMu.Lock()
+	profiles, defaultProfileName = loaded, defaultName
+	profilesMu.Unlock()
+
+	names := make([]string, 0, len(loaded))
+	for name := range loaded {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	log.Printf("인코딩 프로파일 로드: %s (기본 %s)", strings.Join(names, ", "), defaultName)
+
+	return nil
+}
+
+// 설정 파일의 프로파일 목록과 기본 프로파일 이름
+func loadProfileFile(path string) ([]*EncodingProfile, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var file profileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, "", err
+	}
+
+	var loaded []*EncodingProfile
+	seen := make(map[string]bool)
+
+	for i, raw := range file.Profiles {
+		profile, err := DefaultProfile()
+		if err != nil {
+			return nil, "", err
+		}
+
+		// 목록 값은 기존 값과 합쳐지지 않도록 비운 후 읽고, 지정하지 않았으면 기본값 유지
+		ladder, posterWidths, audioOnlyBitrates, extraCodecs := profile.Ladder, profile.PosterWidths, profile.AudioOnlyBitrates, profile.ExtraCodecs
+		profile.Name, profile.Ladder, profile.PosterWidths, profile.AudioOnlyBitrates, profile.ExtraCodecs = "", nil, nil, nil, nil
+		// 키프레임 간격은 지정하지 않으면 이 프로파일의 세그먼트 길이를 따름
+		profile.GOP = 0
+
+		if err := json.Unmarshal(raw, profile); err != nil {
+			return nil, "", fmt.Errorf("프로파일 %d: %v", i, err)
+		}
+
+		var names profileRenditionNames
+		if err := json.Unmarshal(raw, &names); err != nil {
+			return nil, "", fmt.Errorf("프로파일 %d: %v", i, err)
+		}
+
+		switch {
+		case len(names.Renditions) > 0:
+			if profile.Ladder, err = LadderFromNames(names.Renditions); err != nil {
+				return nil, "", fmt.Errorf("프로파일 %s: %v", profile.Name, err)
+			}
+		case profile.Ladder == nil:
+			profile.Ladder = ladder
+		}
+		if profile.PosterWidths == nil {
+			profile.PosterWidths = posterWidths
+		}
+		if profile.AudioOnlyBitrates == nil {
+			profile.AudioOnlyBitrates = audioOnlyBitrates
+		}
+		if profile.ExtraCodecs == nil {
+			profile.ExtraCodecs = extraCodecs
+		}
+
+		if err := validateProfile(profile); err != nil {
+			return nil, "", err
+		}
+		if seen[profile.Name] {
+			return nil, "", fmt.Errorf("중복된 프로파일 이름: %s", profile.Name)
+		}
+		seen[profile.Name] = true
+
+		loaded = append(loaded, profile)
+	}
+
+	return loaded, strings.TrimSpace(file.Default), nil
+}
+
+// 이름으로 인코딩 프로파일 조회 (빈 이름은 기본 프로파일)
+// LoadProfiles 전에는 환경 설정 기본 프로파일만 사용
+func ProfileByName(name string) (*EncodingProfile, error) {
+	name = strings.TrimSpace(name)
+
+	profilesMu.RLock()
+	loaded, defaultName := profiles, defaultProfileName
+	profilesMu.RUnlock()
+
+	if loaded == nil {
+		if name != "" && name != "default" {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+		}
+		return DefaultProfile()
+	}
+
+	if name == "" {
+		name = defaultName
+	}
+
+	profile, ok := loaded[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+	}
+
+	return profile, nil
+}
+
+// 로드된 전체 프로파일 (이름 순서, 시작 시 FFmpeg 기능 확인용)
+func Profiles() []*EncodingProfile {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+
+	list := make([]*EncodingProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		list = append(list, profile)
+	}
+	slices.SortFunc(list, func(a, b *EncodingProfile) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return list
+}
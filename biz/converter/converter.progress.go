@@ -0,0 +1,170 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 인코딩 진행 상태
+type ConversionProgress struct {
+	Percent   float64   `json:"percent"`  // 0 ~ 100
+	OutTime   float64   `json:"out_time"` // 인코딩된 길이 (초)
+	Speed     float64   `json:"speed"`    // 실시간 대비 배속
+	FPS       float64   `json:"fps"`
+	ETA       float64   `json:"eta"` // 남은 예상 시간 (초, 알 수 없으면 -1)
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 진행 상태 구독 콜백
+// ConvertToHLS 를 실행하는 고루틴에서 진행 출력을 읽으며 순서대로 호출하므로 오래 걸리는 작업은 피해야 함
+type ProgressFunc func(job *ConversionJob, progress ConversionProgress)
+
+// 진행 상태 구독 등록
+func (job *ConversionJob) OnProgress(fn ProgressFunc) {
+	job.progressMu.Lock()
+	defer job.progressMu.Unlock()
+
+	job.progressListeners = append(job.progressListeners, fn)
+}
+
+// 현재 진행 상태 조회
+func (job *ConversionJob) CurrentProgress() ConversionProgress {
+	job.progressMu.RLock()
+	defer job.progressMu.RUnlock()
+
+	return job.Progress
+}
+
+// 진행 상태 갱신 후 구독자에게 전달
+func (job *ConversionJob) updateProgress(progress ConversionProgress) {
+	job.progressMu.Lock()
+	job.Progress = progress
+	listeners := append([]ProgressFunc(nil), job.progressListeners...)
+	job.progressMu.Unlock()
+
+	for _, fn := range listeners {
+		fn(job, progress)
+	}
+}
+
+// 인코딩 진행 상태를 구독자에게 전달하며 FFmpeg 실행
+// 반환값은 오류 확인용 FFmpeg 로그(stderr)
+// stallTimeout 이 0 보다 크면 진행 위치 / outputPattern 파일 크기가 멈춘 FFmpeg 를 종료하고 ErrJobStalled 반환
+func runFFmpegWithProgress(ctx context.Context, job *ConversionJob, args []string, outputPattern string, stallTimeout time.Duration) ([]byte, error) {
+	duration := 0.0
+	if job.Source != nil {
+		duration = job.Source.Duration
+	}
+
+	return runWatchedFFmpeg(ctx, job, "FFmpeg", args, outputPattern, stallTimeout, duration, job.updateProgress)
+}
+
+// 진행 상태를 출력하도록 FFmpeg 실행 (-progress pipe:1) 후 진행 없음 감시
+// 진행 출력은 호출한 고루틴에서 파싱하여 update 로 전달 (nil 이면 감시에만 사용)
+func runWatchedFFmpeg(ctx context.Context, job *ConversionJob, step string, args []string, outputPattern string, stallTimeout time.Duration, duration float64, update func(ConversionProgress)) ([]byte, error) {
+	args = append([]string{"-progress", "pipe:1", "-nostats"}, args...)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	cmd := newCommand(ctx, ffmpegBinary(), args...)
+	log.Printf("%s 명령 (Job %s): %v", step, job.ID, cmd.Args)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	// 감시 고루틴이 읽는 인코딩 위치 (float64 비트)
+	var position atomic.Uint64
+	if stallTimeout > 0 {
+		go watchStall(ctx, job.ID, func() float64 {
+			return math.Float64frombits(position.Load())
+		}, outputPattern, stallTimeout, cancel)
+	}
+
+	// Wait 는 파이프를 닫으므로 진행 출력을 모두 읽은 후 호출
+	readProgress(stdout, duration, func(progress ConversionProgress) {
+		position.Store(math.Float64bits(progress.OutTime))
+		if update != nil {
+			update(progress)
+		}
+	})
+
+	err = cmd.Wait()
+	if err != nil && errors.Is(context.Cause(ctx), ErrJobStalled) {
+		return stderr.Bytes(), fmt.Errorf("%w (%s): %w", ErrJobStalled, stallTimeout, err)
+	}
+
+	return stderr.Bytes(), err
+}
+
+// -progress 출력 파싱 - key=value 블록이 progress=continue / end 로 끝날 때마다 갱신
+func readProgress(r io.Reader, duration float64, update func(ConversionProgress)) {
+	var current ConversionProgress
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "out_time_us", "out_time_ms":
+			// out_time_ms 도 실제로는 마이크로초 단위
+			if us, err := strconv.ParseInt(value, 10, 64); err == nil && us >= 0 {
+				current.OutTime = float64(us) / 1e6
+			}
+		case "speed":
+			if speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "x"), 64); err == nil {
+				current.Speed = speed
+			}
+		case "fps":
+			if fps, err := strconv.ParseFloat(value, 64); err == nil {
+				current.FPS = fps
+			}
+		case "progress":
+			current.Percent, current.ETA = progressEstimate(current.OutTime, duration, current.Speed)
+			if value == "end" {
+				current.Percent, current.ETA = 100, 0
+			}
+			current.UpdatedAt = time.Now()
+			update(current)
+		}
+	}
+}
+
+// 진행률 / 남은 예상 시간 계산 (길이나 속도를 모르면 ETA -1)
+func progressEstimate(outTime, duration, speed float64) (float64, float64) {
+	if duration <= 0 {
+		return 0, -1
+	}
+
+	percent := math.Min(outTime/duration*100, 99.9)
+	percent = math.Round(percent*10) / 10
+
+	if speed <= 0 {
+		return percent, -1
+	}
+
+	eta := math.Max(duration-outTime, 0) / speed
+	return percent, math.Round(eta)
+}
@@ -0,0 +1,117 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadProgress(t *testing.T) {
+	type update struct {
+		percent, outTime, speed, fps, eta float64
+	}
+
+	tests := []struct {
+		name     string
+		duration float64
+		output   string
+		want     []update
+	}{
+		{
+			name:     "블록마다 갱신",
+			duration: 20,
+			output: "frame=120\nfps=48.5\nout_time_us=5000000\nspeed=2.0x\nprogress=continue\n" +
+				"fps=50\nout_time_us=10000000\nspeed=2.5x\nprogress=continue\n",
+			want: []update{
+				{25, 5, 2, 48.5, 8},
+				{50, 10, 2.5, 50, 4},
+			},
+		},
+		{
+			name:     "out_time_ms 도 마이크로초 단위",
+			duration: 40,
+			output:   "out_time_ms=10000000\nspeed= 1x\nprogress=continue\n",
+			want:     []update{{25, 10, 1, 0, 30}},
+		},
+		{
+			name:     "속도를 모르면 ETA -1",
+			duration: 10,
+			output:   "out_time_us=1000000\nspeed=N/A\nprogress=continue\n",
+			want:     []update{{10, 1, 0, 0, -1}},
+		},
+		{
+			name:     "음수 위치는 무시",
+			duration: 10,
+			output:   "out_time_us=-9223372036854775807\nprogress=continue\nout_time_us=2000000\nprogress=continue\n",
+			want:     []update{{0, 0, 0, 0, -1}, {20, 2, 0, 0, -1}},
+		},
+		{
+			name:     "끝나기 전에는 100% 로 표시하지 않음",
+			duration: 10,
+			output:   "out_time_us=10500000\nspeed=1x\nprogress=continue\nprogress=end\n",
+			want:     []update{{99.9, 10.5, 1, 0, 0}, {100, 10.5, 1, 0, 0}},
+		},
+		{
+			name:     "길이를 몰라도 끝나면 100%",
+			duration: 0,
+			output:   "out_time_us=3000000\nspeed=1x\nprogress=end\n",
+			want:     []update{{100, 3, 1, 0, 0}},
+		},
+		{
+			name:     "key=value 가 아닌 줄과 공백은 무시",
+			duration: 10,
+			output:   "  \nbitrate\n  out_time_us=4000000  \r\nprogress=continue\r\n",
+			want:     []update{{40, 4, 0, 0, -1}},
+		},
+		{
+			name:     "progress 줄이 없으면 갱신하지 않음",
+			duration: 10,
+			output:   "out_time_us=4000000\nspeed=1x\n",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []update
+			readProgress(strings.NewReader(tt.output), tt.duration, func(p ConversionProgress) {
+				if p.UpdatedAt.IsZero() {
+					t.Error("UpdatedAt 이 설정되지 않았습니다")
+				}
+				got = append(got, update{p.Percent, p.OutTime, p.Speed, p.FPS, p.ETA})
+			})
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("갱신 %d번, want %d번: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("갱신 %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProgressEstimate(t *testing.T) {
+	tests := []struct {
+		name                       string
+		outTime, duration, speed   float64
+		wantPercent, wantRemaining float64
+	}{
+		{"중간 지점", 30, 120, 1.5, 25, 60},
+		{"소수점 한 자리 반올림", 1, 3, 1, 33.3, 2},
+		{"끝을 넘어도 99.9", 130, 120, 2, 99.9, 0},
+		{"속도 0", 30, 120, 0, 25, -1},
+		{"길이 모름", 30, 0, 1, 0, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, eta := progressEstimate(tt.outTime, tt.duration, tt.speed)
+			if percent != tt.wantPercent || eta != tt.wantRemaining {
+				t.Errorf("progressEstimate(%g, %g, %g) = (%g, %g), want (%g, %g)",
+					tt.outTime, tt.duration, tt.speed, percent, eta, tt.wantPercent, tt.wantRemaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 비디오 처리 방식
+const (
+	EncodeModeTranscode = "transcode"
+	EncodeModeRemux     = "remux"
+)
+
+// 원본 비디오 스트림을 그대로 복사하는 렌디션의 Codec 값
+const codecCopy = "copy"
+
+// H.264 프로파일 순위 (낮은 프로파일 스트림은 높은 프로파일 대상에서 그대로 재생 가능)
+var h264ProfileRank = map[string]int{
+	"baseline":             1,
+	"constrained baseline": 1,
+	"main":                 2,
+	"high":                 3,
+}
+
+// 원본 비디오를 재인코딩 없이 세그먼트로 나눌 수 있는지 확인
+// 대상은 원본에 맞춘 래더의 최고 렌디션 - 코덱 / 프로파일 / 레벨 / 해상도 / 비트레이트 / 키프레임 위치가 모두 충족되어야 함
+// 가능하면 최고 렌디션을 대신할 원본 복사 렌디션을, 불가능하면 사유를 반환
+func remuxRendition(ctx context.Context, job *ConversionJob, profile *EncodingProfile) (*Rendition, string) {
+	info := job.MediaInfo
+	source := job.Source
+	target := job.Renditions[0]
+
+	if info.VideoCodec != "h264" {
+		return nil, fmt.Sprintf("코덱 %s", info.VideoCodec)
+	}
+	if info.PixelFormat != "yuv420p" && info.PixelFormat != "yuvj420p" {
+		return nil, fmt.Sprintf("픽셀 형식 %s", info.PixelFormat)
+	}
+
+	sourceRank, ok := h264ProfileRank[strings.ToLower(info.VideoProfile)]
+	if !ok || sourceRank > h264ProfileRank[target.Profile] {
+		return nil, fmt.Sprintf("프로파일 %s > %s", info.VideoProfile, target.Profile)
+	}
+
+	targetLevel := h264LevelIdc(target.Level)
+	if info.VideoLevel <= 0 || info.VideoLevel > targetLevel {
+		return nil, fmt.Sprintf("레벨 %d > %d", info.VideoLevel, targetLevel)
+	}
+
+	// MPEG-TS / fMP4 세그먼트는 회전 / 픽셀 비율 정보를 유지하지 못하므로 원본이 그대로 표시 가능해야 함
+	if source.Rotation%360 != 0 || source.SarNum != source.SarDen {
+		return nil, "회전 또는 비정방형 픽셀"
+	}
+	if source.Width != target.Width || source.Height != target.Height {
+		return nil, fmt.Sprintf("해상도 %dx%d != %dx%d", source.Width, source.Height, target.Width, target.Height)
+	}
+
+	bitrate := info.VideoBitrate
+	if bitrate == 0 {
+		bitrate = info.Bitrate
+	}
+	if bitrate <= 0 || bitrate > target.MaxRate*1000 {
+		return nil, fmt.Sprintf("비트레이트 %dkbps > %dkbps", bitrate/1000, target.MaxRate)
+	}
+
+	// 세그먼트는 키프레임에서만 나눌 수 있으므로 재인코딩 렌디션과 같은 경계 (세그먼트 길이의 배수) 마다 키프레임이 있어야 함
+	// 경계가 어긋나면 ABR 전환 / 키 순환 구간 / DASH SegmentAlignment / 자막 세그먼트가 모두 틀어짐
+	keyframes, err := probeKeyframes(ctx, job.InputFile, source.VideoIndex)
+	if err != nil {
+		return nil, fmt.Sprintf("키프레임 확인 실패: %v", err)
+	}
+	if reason := keyframeAlignment(keyframes, float64(profile.SegmentDuration), info.FrameRate, source.Duration); reason != "" {
+		return nil, reason
+	}
+
+	remuxed := target
+	remuxed.Name = fmt.Sprintf("%dp", min(source.Width, source.Height))
+	remuxed.VideoBitrate = bitrate / 1000
+	remuxed.MaxRate = bitrate / 1000
+	remuxed.BufSize = 0
+	remuxed.Profile = strings.TrimPrefix(strings.ToLower(info.VideoProfile), "constrained ")
+	remuxed.Level = fmt.Sprintf("%d.%d", info.VideoLevel/10, info.VideoLevel%10)
+	remuxed.Codec = codecCopy
+
+	return &remuxed, ""
+}
+
+// H.264 레벨 문자열을 level_idc 로 변환 ("4.1" -> 41)
+func h264LevelIdc(level string) int {
+	var major, minor int
+	if _, err := fmt.Sscanf(level, "%d.%d", &major, &minor); err == nil {
+		return major*10 + minor
+	}
+	if _, err := fmt.Sscanf(level, "%d", &major); err == nil {
+		return major * 10
+	}
+	return 30
+}
+
+// 비디오 스트림 전체의 키프레임 시각 (첫 패킷 기준, 초)
+// 디코딩 없이 패킷 플래그만 확인
+func probeKeyframes(ctx context.Context, inputFile string, videoIndex int) ([]float64, error) {
+	// 작업 제한 시간은 원본 확인 후에 정해지므로 손상된 파일에서 멈추지 않도록 별도 제한
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	cmd := newCommand(ctx, ffprobeBinary(),
+		"-v", "error",
+		"-select_streams", fmt.Sprintf("v:%d", videoIndex),
+		"-show_entries", "packet=pts_time,flags",
+		"-of", "csv=p=0",
+		inputFile,
+	)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("FFprobe 오류: %v", err)
+	}
+
+	return parseKeyframes(output)
+}
+
+// ffprobe 패킷 목록 (pts_time,flags) 에서 키프레임 시각 추출 - 첫 패킷을 0 으로 맞춤
+func parseKeyframes(output []byte) ([]float64, error) {
+	var keyframes []float64
+	start := math.Inf(1)
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ",")
+		if len(fields) < 2 {
+			continue
+		}
+
+		pts, parseErr := strconv.ParseFloat(fields[0], 64)
+		if parseErr != nil {
+			continue
+		}
+		start = min(start, pts)
+
+		if strings.Contains(fields[1], "K") {
+			keyframes = append(keyframes, pts)
+		}
+	}
+
+	if len(keyframes) == 0 {
+		return nil, fmt.Errorf("키프레임을 찾을 수 없습니다")
+	}
+
+	sort.Float64s(keyframes)
+	for i := range keyframes {
+		keyframes[i] -= start
+	}
+
+	return keyframes, nil
+}
+
+// 세그먼트 길이의 배수마다 반 프레임 안에 키프레임이 있는지 확인, 없으면 사유 반환
+// 재인코딩 렌디션은 force_key_frames 로 GOP 배수에서 키프레임을 만들고 세그먼트 길이는 GOP 의 배수
+func keyframeAlignment(keyframes []float64, segmentDuration, frameRate, duration float64) string {
+	if frameRate <= 0 {
+		frameRate = 30
+	}
+	tolerance := 0.5 / frameRate
+
+	if len(keyframes) == 0 || keyframes[0] > tolerance {
+		return "첫 프레임이 키프레임이 아님"
+	}
+
+	end := duration
+	if end <= 0 {
+		end = keyframes[len(keyframes)-1]
+	}
+
+	next := 0
+	for boundary := segmentDuration; boundary < end-tolerance; boundary += segmentDuration {
+		for next < len(keyframes) && keyframes[next] < boundary-tolerance {
+			next++
+		}
+		if next == len(keyframes) || keyframes[next] > boundary+tolerance {
+			return fmt.Sprintf("%.2fs 세그먼트 경계에 키프레임 없음", boundary)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseKeyframes(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []float64
+		wantErr bool
+	}{
+		{"첫 패킷 기준", "1.400000,K__\n1.433333,___\n7.400000,K__\n", []float64{0, 6}, false},
+		{"B 프레임으로 첫 패킷 PTS 가 더 큼", "1.500000,K__\n1.400000,___\n7.400000,K_\n", []float64{0.1, 6}, false},
+		{"N/A 와 빈 줄은 무시", "N/A,K__\n\n0.000000,K__\n", []float64{0}, false},
+		{"키프레임 없음", "0.000000,___\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeyframes([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKeyframes() 오류 = %v, wantErr %v", err, tt.wantErr)
+			}
+			for i := range got {
+				got[i] = float64(int(got[i]*1000+0.5)) / 1000
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseKeyframes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyframeAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyframes []float64
+		duration  float64
+		wantOK    bool
+	}{
+		{"GOP 2초", []float64{0, 2, 4, 6, 8, 10, 12, 14}, 15, true},
+		{"장면 전환 키프레임은 허용", []float64{0, 3.1, 6, 9.7, 12}, 14, true},
+		{"반 프레임 안의 오차는 허용", []float64{0, 6.01, 11.99}, 14, true},
+		{"마지막 경계 이후 남은 구간은 확인하지 않음", []float64{0, 6}, 12, true},
+		{"경계에 키프레임 없음", []float64{0, 5, 10}, 14, false},
+		{"경계 직전 키프레임만 있음", []float64{0, 5.9, 12}, 14, false},
+		{"앞부분 이후 키프레임 간격이 바뀜", []float64{0, 6, 12, 18, 20, 26}, 30, false},
+		{"첫 프레임이 키프레임이 아님", []float64{0.5, 6}, 8, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := keyframeAlignment(tt.keyframes, 6, 30, tt.duration)
+			if (reason == "") != tt.wantOK {
+				t.Errorf("keyframeAlignment() = %q, wantOK %v", reason, tt.wantOK)
+			}
+		})
+	}
+}
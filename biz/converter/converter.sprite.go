@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 스프라이트 시트 설정
+type SpriteOptions struct {
+	Interval int    `json:"interval"` // 썸네일 간격 (초, 0 이면 생성하지 않음)
+	Width    int    `json:"width"`    // 썸네일 한 장의 너비
+	Columns  int    `json:"columns"`
+	Rows     int    `json:"rows"`
+	Format   string `json:"format"` // jpg / webp
+}
+
+const (
+	defaultSpriteInterval = 5
+	defaultSpriteWidth    = 160
+	defaultSpriteColumns  = 10
+	defaultSpriteRows     = 10
+)
+
+// 이미지 형식 값 검증 (빈 값은 JPEG)
+func normalizeImageFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "jpg", "jpeg":
+		return "jpg", nil
+	case "webp":
+		return "webp", nil
+	default:
+		return "", fmt.Errorf("지원하지 않는 이미지 형식: %s", format)
+	}
+}
+
+// 이미지 형식별 FFmpeg 인코더 옵션
+func imageCodecArgs(format string) []string {
+	if format == "webp" {
+		return []string{"-c:v", "libwebp", "-quality", "75"}
+	}
+	return []string{"-q:v", "4"}
+}
+
+// 썸네일을 일정 간격으로 추출하여 스프라이트 시트로 묶고
+// 시간 구간 -> 스프라이트 영역(#xywh=)을 매핑하는 WebVTT 작성
+func generateSprites(ctx context.Context, job *ConversionJob, options SpriteOptions, encodedFileName string) error {
+	duration := job.Source.Duration
+	if duration <= 0 {
+		return fmt.Errorf("영상 길이를 알 수 없습니다")
+	}
+
+	// 원본 비율에 맞춘 썸네일 높이
+	displayWidth, displayHeight := job.Source.DisplaySize()
+	thumbWidth := evenFloor(options.Width)
+	thumbHeight := evenFloor(int(math.Round(float64(thumbWidth) * float64(displayHeight) / float64(displayWidth))))
+
+	spritePattern := fmt.Sprintf("%s_sprite_%%03d.%s", encodedFileName, options.Format)
+
+	args := []string{
+		"-y",
+		"-i", job.InputFile,
+		"-an", "-sn",
+		"-vf", fmt.Sprintf("fps=1/%d,scale=%d:%d,setsar=1,tile=%dx%d", options.Interval, thumbWidth, thumbHeight, options.Columns, options.Rows),
+	}
+	args = append(args, imageCodecArgs(options.Format)...)
+	args = append(args,
+		"-start_number", "0",
+		filepath.Join(job.OutputDir, spritePattern),
+	)
+
+	if err := runUnwatchedFFmpegStep(ctx, job, "스프라이트", args); err != nil {
+		return err
+	}
+
+	// 썸네일 한 장당 하나의 큐
+	thumbCount := int(math.Ceil(duration / float64(options.Interval)))
+	perSheet := options.Columns * options.Rows
+
+	var vtt strings.Builder
+	vtt.WriteString("WEBVTT\n\n")
+
+	for i := 0; i < thumbCount; i++ {
+		sheet := i / perSheet
+		position := i % perSheet
+		x := (position % options.Columns) * thumbWidth
+		y := (position / options.Columns) * thumbHeight
+
+		start := float64(i * options.Interval)
+		end := math.Min(float64((i+1)*options.Interval), duration)
+
+		fmt.Fprintf(&vtt, "%s --> %s\n", vttTimestamp(start), vttTimestamp(end))
+		fmt.Fprintf(&vtt, "%s#xywh=%d,%d,%d,%d\n\n", fmt.Sprintf(spritePattern, sheet), x, y, thumbWidth, thumbHeight)
+	}
+
+	sheetCount := (thumbCount + perSheet - 1) / perSheet
+	for sheet := 0; sheet < sheetCount; sheet++ {
+		job.SpriteFiles = append(job.SpriteFiles, filepath.Join(job.OutputDir, fmt.Sprintf(spritePattern, sheet)))
+	}
+
+	storyboardPath := filepath.Join(job.OutputDir, fmt.Sprintf("%s_storyboard.vtt", encodedFileName))
+	if err := os.WriteFile(storyboardPath, []byte(vtt.String()), 0644); err != nil {
+		return err
+	}
+	job.StoryboardFile = storyboardPath
+
+	return nil
+}
+
+// WebVTT 타임스탬프 (HH:MM:SS.mmm)
+func vttTimestamp(seconds float64) string {
+	millis := int64(math.Round(seconds * 1000))
+	hours := millis / 3600000
+	minutes := (millis % 3600000) / 60000
+	secs := (millis % 60000) / 1000
+
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, secs, millis%1000)
+}
@@ -3,9 +3,10 @@ package converter
 var UpdateFileName = `
 	UPDATE video_table
 	SET hls_file_name = $1,
-		convert_status = $2
-	WHERE video_seq = $3 AND
-		user_id = $4
+		poster_file_name = NULLIF($2, ''),
+		convert_status = $3
+	WHERE video_seq = $4 AND
+		user_id = $5
 `
 
 var UpdateConvertStatus = `
@@ -14,3 +15,32 @@ var UpdateConvertStatus = `
 	WHERE video_seq = $2 AND
 		user_id = $3
 `
+
+var DeleteContentKeys = `
+	DELETE FROM video_key_table
+	WHERE video_seq = $1 AND user_id = $2
+`
+
+var InsertContentKey = `
+	INSERT INTO video_key_table (video_seq, user_id, key_method, encrypted_key, key_iv, key_uri,
+		key_index, segment_start, segment_end)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+var UpsertMediaInfo = `
+	INSERT INTO video_media_table (video_seq, user_id, duration, width, height, display_width, display_height,
+		rotation, video_codec, audio_codec, frame_rate, bitrate, media_info)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	ON CONFLICT (video_seq, user_id) DO UPDATE
+	SET duration = EXCLUDED.duration,
+		width = EXCLUDED.width,
+		height = EXCLUDED.height,
+		display_width = EXCLUDED.display_width,
+		display_height = EXCLUDED.display_height,
+		rotation = EXCLUDED.rotation,
+		video_codec = EXCLUDED.video_codec,
+		audio_codec = EXCLUDED.audio_codec,
+		frame_rate = EXCLUDED.frame_rate,
+		bitrate = EXCLUDED.bitrate,
+		media_info = EXCLUDED.media_info
+`
@@ -0,0 +1,385 @@
+package converter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// 자막 트랙 구조체
+type SubtitleTrack struct {
+	FilePath    string `json:"file_path,omitempty"` // 자막 파일 (.srt / .ass / .vtt) 또는 내장 자막의 원본 영상
+	StreamIndex int    `json:"stream_index"`        // 파일 내 자막 스트림 순서 (0:s:N)
+	Embedded    bool   `json:"embedded,omitempty"`  // 원본 영상에 포함된 자막 여부
+	Language    string `json:"language"`            // BCP-47 언어 코드 (예: ko, en)
+	Name        string `json:"name,omitempty"`
+	Default     bool   `json:"default,omitempty"`
+	Playlist    string `json:"playlist,omitempty"` // 변환된 WebVTT 미디어 플레이리스트 경로
+}
+
+// 변환하지 못한 자막 트랙
+type SkippedSubtitle struct {
+	FilePath    string `json:"file_path,omitempty"`
+	StreamIndex int    `json:"stream_index"`
+	Codec       string `json:"codec,omitempty"`
+	Language    string `json:"language,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+// WebVTT 로 변환 가능한 텍스트 자막 코덱
+var textSubtitleCodecs = map[string]bool{
+	"subrip":   true,
+	"srt":      true,
+	"ass":      true,
+	"ssa":      true,
+	"mov_text": true,
+	"webvtt":   true,
+	"text":     true,
+}
+
+// ISO 639-2 -> BCP-47 (스트림 메타데이터는 3글자 코드 사용)
+var iso639Alpha2 = map[string]string{
+	"kor": "ko",
+	"eng": "en",
+	"jpn": "ja",
+	"chi": "zh",
+	"zho": "zh",
+	"fre": "fr",
+	"fra": "fr",
+	"ger": "de",
+	"deu": "de",
+	"spa": "es",
+}
+
+// 스트림 언어 태그를 BCP-47 코드로 변환 (und / 빈 값은 그대로 비움)
+func normalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language == "" || language == "und" {
+		return ""
+	}
+	if alpha2, ok := iso639Alpha2[language]; ok {
+		return alpha2
+	}
+	return language
+}
+
+// 원본에 포함된 텍스트 자막을 자막 트랙으로 추가하고
+// 이미지 자막(PGS / VobSub 등)은 건너뛴 목록에 기록
+func collectEmbeddedSubtitles(job *ConversionJob) {
+	for _, stream := range job.Source.SubtitleStreams {
+		if !textSubtitleCodecs[stream.Codec] {
+			job.SkippedSubtitles = append(job.SkippedSubtitles, SkippedSubtitle{
+				FilePath:    job.InputFile,
+				StreamIndex: stream.Index,
+				Codec:       stream.Codec,
+				Language:    normalizeLanguage(stream.Language),
+				Reason:      "이미지 기반 자막은 WebVTT 로 변환할 수 없습니다",
+			})
+			continue
+		}
+
+		job.Subtitles = append(job.Subtitles, SubtitleTrack{
+			FilePath:    job.InputFile,
+			StreamIndex: stream.Index,
+			Embedded:    true,
+			Language:    normalizeLanguage(stream.Language),
+			Name:        stream.Title,
+			Default:     stream.Default,
+		})
+	}
+}
+
+// 마스터 플레이리스트의 자막 그룹 ID
+const subtitleGroupID = "subs"
+
+// 트랙 이름이 없을 때 사용할 언어 표시명
+var languageNames = map[string]string{
+	"ko": "한국어",
+	"en": "English",
+	"ja": "日本語",
+	"zh": "中文",
+}
+
+// 자막 트랙 표시 이름
+func (t *SubtitleTrack) DisplayName() string {
+	return trackDisplayName(t.Name, t.Language)
+}
+
+// 트랙 이름 -> 언어 표시명 -> 언어 코드 순으로 표시 이름 결정
+func trackDisplayName(name, language string) string {
+	if name != "" {
+		return name
+	}
+	if displayName, ok := languageNames[strings.ToLower(language)]; ok {
+		return displayName
+	}
+	if language != "" {
+		return language
+	}
+	return "Unknown"
+}
+
+// 자막 미디어 플레이리스트 파일명
+func subtitlePlaylistName(encodedFileName string, index int) string {
+	return fmt.Sprintf("%s_sub%d.m3u8", encodedFileName, index)
+}
+
+// WebVTT 큐
+type vttCue struct {
+	Start    float64
+	End      float64
+	Settings string
+	Text     string
+}
+
+// 모든 자막 트랙을 비디오 세그먼트 경계에 맞춘 WebVTT 세그먼트로 변환
+// timestampMap 은 세그먼트 암호화 전에 subtitleTimestampMap 으로 확인한 값
+// 변환에 실패한 트랙은 제외하고 계속 진행
+func convertSubtitles(ctx context.Context, job *ConversionJob, encodedFileName, timestampMap string) {
+	if len(job.Subtitles) == 0 {
+		return
+	}
+
+	segments, err := parseMediaPlaylist(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, job.Renditions[0].Name)))
+	if err != nil {
+		log.Printf("자막 변환 실패 (Job %s): 비디오 플레이리스트 확인 오류: %v", job.ID, err)
+		return
+	}
+
+	for i := range job.Subtitles {
+		track := &job.Subtitles[i]
+
+		cues, cueErr := loadSubtitleCues(ctx, job, track, i)
+		if cueErr != nil {
+			log.Printf("자막 변환 실패 (Job %s): %s: %v", job.ID, track.FilePath, cueErr)
+			job.skipSubtitle(track, fmt.Sprintf("변환 실패: %v", cueErr))
+			continue
+		}
+
+		playlistPath, writeErr := writeSubtitleSegments(job, encodedFileName, i, cues, segments, timestampMap)
+		if writeErr != nil {
+			log.Printf("자막 세그먼트 작성 실패 (Job %s): %s: %v", job.ID, track.FilePath, writeErr)
+			job.skipSubtitle(track, fmt.Sprintf("세그먼트 작성 실패: %v", writeErr))
+			continue
+		}
+
+		track.Playlist = playlistPath
+	}
+}
+
+// 변환 실패한 자막 트랙 기록
+func (job *ConversionJob) skipSubtitle(track *SubtitleTrack, reason string) {
+	job.SkippedSubtitles = append(job.SkippedSubtitles, SkippedSubtitle{
+		FilePath:    track.FilePath,
+		StreamIndex: track.StreamIndex,
+		Language:    track.Language,
+		Reason:      reason,
+	})
+}
+
+// 비디오 타임라인과 자막 타임라인을 맞추는 X-TIMESTAMP-MAP 헤더
+// MPEG-TS 는 첫 세그먼트의 비디오 PTS 기준, fMP4 는 0 기준
+// FFmpeg 단계에서 암호화된 세그먼트는 key 로 복호화해서 확인 (키 순환 암호화 전에 호출)
+func subtitleTimestampMap(job *ConversionJob, encodedFileName string, key *contentKey) (string, error) {
+	var pts int64
+	if job.SegmentType != SegmentTypeFMP4 {
+		segments, err := parseMediaPlaylist(filepath.Join(job.OutputDir, variantPlaylistName(encodedFileName, job.Renditions[0].Name)))
+		if err != nil {
+			return "", fmt.Errorf("비디오 플레이리스트 확인 오류: %v", err)
+		}
+		if len(segments) == 0 {
+			return "", fmt.Errorf("비디오 세그먼트가 없습니다")
+		}
+
+		data, err := os.ReadFile(filepath.Join(job.OutputDir, segments[0].URI))
+		if err != nil {
+			return "", err
+		}
+		if key != nil {
+			if data, err = decryptSegmentData(data, key); err != nil {
+				return "", fmt.Errorf("첫 세그먼트 복호화 오류: %v", err)
+			}
+		}
+
+		if pts, err = firstVideoPTS(data); err != nil {
+			return "", fmt.Errorf("첫 세그먼트 PTS 확인 오류: %v", err)
+		}
+	}
+
+	return fmt.Sprintf("X-TIMESTAMP-MAP=MPEGTS:%d,LOCAL:00:00:00.000", pts), nil
+}
+
+// 자막 파일을 WebVTT 로 변환 후 큐 목록 파싱
+func loadSubtitleCues(ctx context.Context, job *ConversionJob, track *SubtitleTrack, index int) ([]vttCue, error) {
+	tempDir, err := os.MkdirTemp("", "hls_sub_")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	vttPath := filepath.Join(tempDir, fmt.Sprintf("sub%d.vtt", index))
+
+	args := []string{"-y"}
+
+	// UTF-8 이 아닌 자막 파일은 국내 업로드에 흔한 CP949 로 간주
+	if !track.Embedded {
+		if raw, readErr := os.ReadFile(track.FilePath); readErr == nil && !utf8.Valid(raw) {
+			args = append(args, "-sub_charenc", "CP949")
+		}
+	}
+
+	args = append(args,
+		"-i", track.FilePath,
+		"-map", fmt.Sprintf("0:s:%d", track.StreamIndex),
+		"-c:s", "webvtt",
+		vttPath,
+	)
+
+	if err := runFFmpegStep(ctx, job, "자막", args); err != nil {
+		return nil, err
+	}
+
+	return parseWebVTT(vttPath)
+}
+
+// WebVTT 파일의 큐 파싱 (헤더 / NOTE / STYLE 블록은 무시)
+func parseWebVTT(path string) ([]vttCue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cues []vttCue
+	var current *vttCue
+	var text []string
+
+	flush := func() {
+		if current != nil {
+			current.Text = strings.Join(text, "\n")
+			cues = append(cues, *current)
+		}
+		current = nil
+		text = nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		if current != nil {
+			text = append(text, line)
+			continue
+		}
+
+		if !strings.Contains(line, "-->") {
+			// 큐 식별자, WEBVTT 헤더, NOTE 등
+			continue
+		}
+
+		parts := strings.SplitN(line, "-->", 2)
+		endFields := strings.Fields(parts[1])
+		if len(endFields) == 0 {
+			continue
+		}
+
+		start, startErr := parseVTTTimestamp(strings.TrimSpace(parts[0]))
+		end, endErr := parseVTTTimestamp(endFields[0])
+		if startErr != nil || endErr != nil {
+			continue
+		}
+
+		current = &vttCue{Start: start, End: end, Settings: strings.Join(endFields[1:], " ")}
+	}
+	flush()
+
+	return cues, scanner.Err()
+}
+
+// WebVTT 타임스탬프 파싱 (HH:MM:SS.mmm 또는 MM:SS.mmm)
+func parseVTTTimestamp(value string) (float64, error) {
+	parts := strings.Split(value, ":")
+
+	var hours, minutes int
+	var seconds float64
+	var err error
+
+	switch len(parts) {
+	case 3:
+		_, err = fmt.Sscanf(value, "%d:%d:%f", &hours, &minutes, &seconds)
+	case 2:
+		_, err = fmt.Sscanf(value, "%d:%f", &minutes, &seconds)
+	default:
+		err = fmt.Errorf("잘못된 타임스탬프: %s", value)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(hours*3600+minutes*60) + seconds, nil
+}
+
+// 비디오 세그먼트와 같은 구간으로 WebVTT 세그먼트 및 미디어 플레이리스트 작성
+// 세그먼트 경계에 걸친 큐는 겹치는 모든 세그먼트에 포함
+func writeSubtitleSegments(job *ConversionJob, encodedFileName string, index int, cues []vttCue, segments []mediaSegment, timestampMap string) (string, error) {
+	var playlist strings.Builder
+	targetDuration := 0.0
+	for _, seg := range segments {
+		targetDuration = math.Max(targetDuration, seg.Duration)
+	}
+
+	playlist.WriteString("#EXTM3U\n")
+	playlist.WriteString("#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&playlist, "#EXT-X-TARGETDURATION:%d\n", int(math.Ceil(targetDuration)))
+	playlist.WriteString("#EXT-X-MEDIA-SEQUENCE:0\n")
+	playlist.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+
+	segmentStart := 0.0
+	for segIndex, seg := range segments {
+		segmentEnd := segmentStart + seg.Duration
+
+		var body strings.Builder
+		body.WriteString("WEBVTT\n")
+		body.WriteString(timestampMap + "\n\n")
+
+		for _, cue := range cues {
+			if cue.End <= segmentStart || cue.Start >= segmentEnd {
+				continue
+			}
+
+			fmt.Fprintf(&body, "%s --> %s", vttTimestamp(cue.Start), vttTimestamp(cue.End))
+			if cue.Settings != "" {
+				body.WriteString(" " + cue.Settings)
+			}
+			body.WriteString("\n" + cue.Text + "\n\n")
+		}
+
+		segmentName := fmt.Sprintf("%s_sub%d_%03d.vtt", encodedFileName, index, segIndex)
+		if err := os.WriteFile(filepath.Join(job.OutputDir, segmentName), []byte(body.String()), 0644); err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(&playlist, "#EXTINF:%.6f,\n%s\n", seg.Duration, segmentName)
+		segmentStart = segmentEnd
+	}
+
+	playlist.WriteString("#EXT-X-ENDLIST\n")
+
+	playlistPath := filepath.Join(job.OutputDir, subtitlePlaylistName(encodedFileName, index))
+	if err := os.WriteFile(playlistPath, []byte(playlist.String()), 0644); err != nil {
+		return "", err
+	}
+
+	return playlistPath, nil
+}
@@ -0,0 +1,66 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSubtitleTimestampMap(t *testing.T) {
+	segment := joinTestPackets(
+		tsTestPacket(true, nil, pesTestHeader(0xC0, 100)),
+		tsTestPacket(true, nil, pesTestHeader(0xE0, 126000)),
+	)
+	key := &contentKey{Key: make([]byte, 16), IV: []byte("0123456789abcdef")}
+
+	tests := []struct {
+		name        string
+		segmentType string
+		segment     []byte
+		encrypt     bool
+		want        string
+		wantErr     bool
+	}{
+		{"MPEG-TS 첫 비디오 PTS", SegmentTypeMPEGTS, segment, false, "X-TIMESTAMP-MAP=MPEGTS:126000,LOCAL:00:00:00.000", false},
+		{"FFmpeg 에서 암호화된 세그먼트는 복호화", SegmentTypeMPEGTS, segment, true, "X-TIMESTAMP-MAP=MPEGTS:126000,LOCAL:00:00:00.000", false},
+		{"fMP4 는 0 기준", SegmentTypeFMP4, nil, false, "X-TIMESTAMP-MAP=MPEGTS:0,LOCAL:00:00:00.000", false},
+		{"PTS 를 읽지 못하면 오류", SegmentTypeMPEGTS, joinTestPackets(tsTestPacket(true, nil, pesTestHeader(0xC0, 0))), false, "", true},
+		{"키 없이 암호화된 세그먼트는 오류", SegmentTypeMPEGTS, segment, true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			job := &ConversionJob{OutputDir: dir, SegmentType: tt.segmentType, Renditions: []Rendition{{Name: "720p"}}}
+
+			playlist := "#EXTM3U\n#EXTINF:6.000,\nabc_720p_000.ts\n#EXT-X-ENDLIST\n"
+			if err := os.WriteFile(filepath.Join(dir, variantPlaylistName("abc", "720p")), []byte(playlist), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			segmentPath := filepath.Join(dir, "abc_720p_000.ts")
+			if err := os.WriteFile(segmentPath, tt.segment, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var ffmpegKey *contentKey
+			if tt.encrypt {
+				if err := encryptSegmentFile(segmentPath, key); err != nil {
+					t.Fatal(err)
+				}
+				// 오류 케이스는 키를 넘기지 않아 암호화된 데이터를 그대로 읽음
+				if !tt.wantErr {
+					ffmpegKey = key
+				}
+			}
+
+			got, err := subtitleTimestampMap(job, "abc", ffmpegKey)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("subtitleTimestampMap() 오류 = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("subtitleTimestampMap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 진행 없음 판단 기본 시간 (초)
+const defaultStallTimeout = 120
+
+// 작업의 진행 없음 제한 시간 (0 이면 감시하지 않음)
+func (job *ConversionJob) stallTimeout() time.Duration {
+	if job.Profile == nil {
+		return 0
+	}
+	return time.Duration(job.Profile.StallTimeout) * time.Second
+}
+
+// 이번 시도에서 생성하는 출력 파일 패턴 (출력 이름이 정해지기 전에는 빈 값)
+func (job *ConversionJob) outputPattern() string {
+	if job.outputName == "" {
+		return ""
+	}
+	return filepath.Join(job.OutputDir, job.outputName+"*")
+}
+
+// 멈춘 FFmpeg 감시
+// -progress 의 인코딩 위치와 출력 파일 크기가 stallTimeout 동안 모두 그대로이면 ErrJobStalled 로 cancel 호출
+func watchStall(ctx context.Context, jobID string, position func() float64, outputPattern string, stallTimeout time.Duration, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(max(stallTimeout/4, time.Second))
+	defer ticker.Stop()
+
+	lastOutTime := position()
+	lastSize := outputSize(outputPattern)
+	lastChange := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			outTime := position()
+			size := outputSize(outputPattern)
+
+			if outTime != lastOutTime || size != lastSize {
+				lastOutTime, lastSize, lastChange = outTime, size, now
+				continue
+			}
+
+			if now.Sub(lastChange) >= stallTimeout {
+				log.Printf("FFmpeg 진행 없음 (Job %s): %s 동안 변화 없음 (위치 %.1fs, 출력 %d bytes)", jobID, stallTimeout, outTime, size)
+				cancel(ErrJobStalled)
+				return
+			}
+		}
+	}
+}
+
+// 패턴에 해당하는 출력 파일 전체 크기
+func outputSize(pattern string) int64 {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return 0
+	}
+
+	var total int64
+	for _, match := range matches {
+		if info, statErr := os.Stat(match); statErr == nil {
+			total += info.Size()
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,75 @@
+package configs
+
+import "os"
+
+type ConverterConf struct {
+	// 이름별 인코딩 프로파일 설정 파일 경로 (JSON, 비어 있으면 환경 설정 기본 프로파일만 사용)
+	ProfilesFile string
+	// 쉼표로 구분된 렌디션 목록 (예: 1080p,720p,480p,360p)
+	Renditions string
+	// 세그먼트 길이 (초, 기본 6)
+	SegmentDuration string
+	// H.264 와 함께 만들 추가 코덱 목록 (예: hevc,av1 / 빈 값이면 H.264 만)
+	ExtraCodecs string
+	// 세그먼트 컨테이너 (mpegts / fmp4)
+	SegmentType string
+	// HLS 와 함께 DASH 매니페스트 생성 여부 (true / false)
+	GenerateDash string
+	// HLS 암호화 방식 (none / aes-128)
+	Encryption string
+	// EXT-X-KEY URI 템플릿 (예: https://api.example.com/keys/{userId}/{videoSeq})
+	KeyURITemplate string
+	// 키 순환 주기 - 세그먼트 개수 (0 또는 빈 값이면 단일 키)
+	KeyRotationSegments string
+	// I-frame 플레이리스트 생성 여부 (기본 true)
+	IFramePlaylists string
+	// 스프라이트 썸네일 추출 간격 (초, 0 이면 생성하지 않음)
+	SpriteInterval string
+	// 스프라이트 이미지 형식 (jpg / webp)
+	SpriteFormat string
+	// 포스터 이미지 너비 목록 (예: 1280,640,320 / 0 이면 생성하지 않음)
+	PosterWidths string
+	// 미리보기 클립 길이 (초, 0 이면 생성하지 않음) / 너비 / 구간 수
+	PreviewDuration string
+	PreviewWidth    string
+	PreviewExcerpts string
+	// 오디오 전용 변환 비트레이트 목록 (kbps, 예: 192,128,64)
+	AudioOnlyBitrates string
+	// 비디오 변환 시 추가할 저대역폭 오디오 전용 variant 비트레이트 (kbps, 0 이면 생성하지 않음)
+	AudioFallbackBitrate string
+	// EBU R128 라우드니스 정규화 목표값 (LUFS, 예: -16 / 빈 값이면 정규화하지 않음)
+	LoudnessTarget string
+	// 호환되는 원본은 재인코딩 없이 세그먼트만 나눌지 여부 (true / false)
+	AllowRemux string
+	// 작업 최대 실행 시간 배수 (원본 길이 x N, 최소 10분)
+	TimeoutFactor string
+	// FFmpeg 진행이 없을 때 종료하기까지의 시간 (초, 0 이면 감시하지 않음)
+	StallTimeout string
+}
+
+var ConverterConfig ConverterConf
+
+func SetConverterConfig() {
+	ConverterConfig.ProfilesFile = os.Getenv("HLS_PROFILES_FILE")
+	ConverterConfig.Renditions = os.Getenv("HLS_RENDITIONS")
+	ConverterConfig.SegmentDuration = os.Getenv("HLS_SEGMENT_DURATION")
+	ConverterConfig.ExtraCodecs = os.Getenv("HLS_EXTRA_CODECS")
+	ConverterConfig.SegmentType = os.Getenv("HLS_SEGMENT_TYPE")
+	ConverterConfig.GenerateDash = os.Getenv("HLS_GENERATE_DASH")
+	ConverterConfig.Encryption = os.Getenv("HLS_ENCRYPTION")
+	ConverterConfig.KeyURITemplate = os.Getenv("HLS_KEY_URI")
+	ConverterConfig.KeyRotationSegments = os.Getenv("HLS_KEY_ROTATION_SEGMENTS")
+	ConverterConfig.IFramePlaylists = os.Getenv("HLS_IFRAME_PLAYLISTS")
+	ConverterConfig.SpriteInterval = os.Getenv("HLS_SPRITE_INTERVAL")
+	ConverterConfig.SpriteFormat = os.Getenv("HLS_SPRITE_FORMAT")
+	ConverterConfig.PosterWidths = os.Getenv("HLS_POSTER_WIDTHS")
+	ConverterConfig.PreviewDuration = os.Getenv("HLS_PREVIEW_DURATION")
+	ConverterConfig.PreviewWidth = os.Getenv("HLS_PREVIEW_WIDTH")
+	ConverterConfig.PreviewExcerpts = os.Getenv("HLS_PREVIEW_EXCERPTS")
+	ConverterConfig.AudioOnlyBitrates = os.Getenv("HLS_AUDIO_ONLY_BITRATES")
+	ConverterConfig.AudioFallbackBitrate = os.Getenv("HLS_AUDIO_FALLBACK_BITRATE")
+	ConverterConfig.LoudnessTarget = os.Getenv("HLS_LOUDNORM_TARGET")
+	ConverterConfig.AllowRemux = os.Getenv("HLS_REMUX")
+	ConverterConfig.TimeoutFactor = os.Getenv("HLS_JOB_TIMEOUT_FACTOR")
+	ConverterConfig.StallTimeout = os.Getenv("HLS_STALL_TIMEOUT")
+}
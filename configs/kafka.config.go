@@ -8,6 +8,8 @@ type KafkaConf struct {
 	OutputTopic string
 	GroupId     string
 	ConsumerId  string
+	// 진행 없음으로 종료된 변환 재시도 횟수
+	StallRetries string
 }
 
 var KafkaConfig KafkaConf
@@ -18,4 +20,5 @@ func SetKafkaConfig() {
 	KafkaConfig.OutputTopic = os.Getenv("KAFKA_OUTPUT_TOPIC")
 	KafkaConfig.GroupId = os.Getenv("KAFKA_GROUP")
 	KafkaConfig.ConsumerId = os.Getenv("KAFKA_CONSUMER")
+	KafkaConfig.StallRetries = os.Getenv("KAFKA_STALL_RETRIES")
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/donghquinn/hls_converter/biz/converter"
+	"github.com/donghquinn/hls_converter/configs"
+)
+
+// HealthResponse is served on /health for container and load balancer probes
+type HealthResponse struct {
+	Status    string                        `json:"status"`
+	StartedAt time.Time                     `json:"startedAt"`
+	FFmpeg    *converter.FFmpegCapabilities `json:"ffmpeg,omitempty"`
+}
+
+// startHealthServer serves the health endpoint on APP_HOST:APP_PORT; it is skipped when no port is configured
+func startHealthServer() {
+	if configs.GlobalConfiguration.AppPort == "" {
+		log.Println("APP_PORT is not set, health endpoint disabled")
+		return
+	}
+
+	startedAt := time.Now()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(HealthResponse{
+			Status:    "ok",
+			StartedAt: startedAt,
+			FFmpeg:    converter.Capabilities(),
+		})
+	})
+
+	addr := net.JoinHostPort(configs.GlobalConfiguration.AppHost, configs.GlobalConfiguration.AppPort)
+	log.Printf("Health endpoint listening on %s/health", addr)
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("Health server stopped: %v", err)
+		}
+	}()
+}
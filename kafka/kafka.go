@@ -3,10 +3,12 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,19 +24,55 @@ import (
 // 	OutputPath string `json:"outputPath,omitempty"`
 // }
 
+// SubtitleMessage describes a sidecar subtitle file uploaded with the video
+type SubtitleMessage struct {
+	FilePath string `json:"filePath"`
+	Language string `json:"language"`
+	Name     string `json:"name,omitempty"`
+	Default  bool   `json:"default,omitempty"`
+}
+
 type KafakaMessage struct {
-	UserId   string `json:"userId"`
-	FileName string `json:"filePath"`
+	UserId      string `json:"userId"`
+	FileName    string `json:"filePath"`
+	Profile     string `json:"profile,omitempty"`     // encoding profile name, empty uses the default profile
+	SegmentType string `json:"segmentType,omitempty"` // mpegts / fmp4, empty uses profile default
+	Encryption  string `json:"encryption,omitempty"`  // none / aes-128, empty uses profile default
+
+	DefaultAudio string `json:"defaultAudio,omitempty"` // language code or audio stream index, empty uses source default
+
+	Subtitles []SubtitleMessage `json:"subtitles,omitempty"`
 }
 
 // CompletionMessage represents the message to be sent after conversion
 type CompletionMessage struct {
-	RequestID    string    `json:"requestId"`
-	Status       string    `json:"status"`
-	InputFile    string    `json:"inputFile"`
-	OutputFile   string    `json:"outputFile"`
-	ErrorMessage string    `json:"errorMessage,omitempty"`
-	CompletedAt  time.Time `json:"completedAt"`
+	RequestID    string                          `json:"requestId"`
+	Status       string                          `json:"status"`
+	InputFile    string                          `json:"inputFile"`
+	OutputFile   string                          `json:"outputFile"`
+	HlsManifest  string                          `json:"hlsManifest"`
+	DashManifest string                          `json:"dashManifest,omitempty"`
+	IFrameLists  []string                        `json:"iFramePlaylists,omitempty"`
+	Sprites      []string                        `json:"sprites,omitempty"`
+	Storyboard   string                          `json:"storyboard,omitempty"`
+	Poster       string                          `json:"poster,omitempty"`
+	Posters      []string                        `json:"posters,omitempty"`
+	PreviewMp4   string                          `json:"previewMp4,omitempty"`
+	PreviewWebp  string                          `json:"previewWebp,omitempty"`
+	SkippedSubs  []converter.SkippedSubtitle     `json:"skippedSubtitles,omitempty"`
+	Profile      string                          `json:"profile,omitempty"`
+	EncodeMode   string                          `json:"encodeMode,omitempty"` // transcode / remux
+	AudioOnly    bool                            `json:"audioOnly,omitempty"`
+	Duration     float64                         `json:"duration,omitempty"` // seconds
+	MediaInfo    *converter.MediaInfo            `json:"mediaInfo,omitempty"`
+	Loudness     []converter.LoudnessMeasurement `json:"loudness,omitempty"`
+	SegmentType  string                          `json:"segmentType,omitempty"`
+	Encryption   string                          `json:"encryption,omitempty"`
+	Reason       string                          `json:"failureReason,omitempty"` // invalid_input / disk_full / stalled / timeout ...
+	ErrorType    string                          `json:"errorType,omitempty"`     // user / infrastructure
+	ErrorMessage string                          `json:"errorMessage,omitempty"`
+	ErrorDetail  string                          `json:"errorDetail,omitempty"`
+	CompletedAt  time.Time                       `json:"completedAt"`
 }
 
 type KafkaInterface struct {
@@ -46,6 +84,7 @@ type KafkaInterface struct {
 	GroupID      string
 	ConsumerID   string
 	OutputDir    string
+	StallRetries int // Retries for conversions killed by the stall watchdog
 }
 
 func NewKafkaInstance() (*KafkaInterface, error) {
@@ -84,6 +123,14 @@ func NewKafkaInstance() (*KafkaInterface, error) {
 		}
 	}
 
+	stallRetries := 1
+	if kafkaConfig.StallRetries != "" {
+		stallRetries, err = strconv.Atoi(kafkaConfig.StallRetries)
+		if err != nil || stallRetries < 0 {
+			return nil, fmt.Errorf("invalid stall retry count: %s", kafkaConfig.StallRetries)
+		}
+	}
+
 	// Create consumer with explicit broker address
 	consumer := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:        []string{kafkaConfig.Broker}, // 브로커 주소 명시적으로 사용
@@ -120,6 +167,7 @@ func NewKafkaInstance() (*KafkaInterface, error) {
 		GroupID:      kafkaConfig.GroupId,
 		ConsumerID:   kafkaConfig.ConsumerId,
 		OutputDir:    configs.GlobalConfiguration.OutputDir,
+		StallRetries: stallRetries,
 	}
 
 	return instance, nil
@@ -230,27 +278,103 @@ func (k *KafkaInterface) processMessage(ctx context.Context, m kafka.Message) er
 		return fmt.Errorf("input file not found: %s", kafkaMsg.FileName)
 	}
 
+	// Resolve the encoding profile before doing any work; an unknown name is a bad message,
+	// so mark the row invalid and report it like any other user error instead of failing silently
+	profile, err := converter.ProfileByName(kafkaMsg.Profile)
+	if err != nil {
+		log.Printf("[KAFKA] Invalid encoding profile for request %s: %v", videoSeq, err)
+		if statusErr := converter.ChangeConvertStatus(kafkaMsg.UserId, videoSeq, "INVALID"); statusErr != nil {
+			log.Printf("[KAFKA] Failed to update convert status for request %s: %v", videoSeq, statusErr)
+		}
+
+		completionMsg := CompletionMessage{
+			RequestID:   kafkaMsg.UserId,
+			Status:      "failed",
+			InputFile:   kafkaMsg.FileName,
+			Profile:     kafkaMsg.Profile,
+			SegmentType: kafkaMsg.SegmentType,
+			Encryption:  kafkaMsg.Encryption,
+			CompletedAt: time.Now(),
+		}
+		completionMsg.setFailure(err)
+		k.publishCompletion(ctx, completionMsg)
+
+		return nil
+	}
+
 	// Create output directory
 	outputDir := filepath.Join(k.OutputDir, kafkaMsg.UserId)
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %v", err)
 	}
 
+	// Sidecar subtitles; missing files are skipped rather than failing the video
+	var subtitles []converter.SubtitleTrack
+	for _, sub := range kafkaMsg.Subtitles {
+		if _, err := os.Stat(sub.FilePath); err != nil {
+			log.Printf("[KAFKA] Subtitle file not found, skipping: %s", sub.FilePath)
+			continue
+		}
+		subtitles = append(subtitles, converter.SubtitleTrack{
+			FilePath: sub.FilePath,
+			Language: sub.Language,
+			Name:     sub.Name,
+			Default:  sub.Default,
+		})
+	}
+
 	// Create a conversion job
-	job := &converter.ConversionJob{
-		VideoSeq:  videoSeq,
-		ID:        kafkaMsg.UserId,
-		InputFile: kafkaMsg.FileName,
-		OutputDir: outputDir,
-		Status:    "pending",
-		CreatedAt: time.Now(),
+	newJob := func() *converter.ConversionJob {
+		job := &converter.ConversionJob{
+			VideoSeq:    videoSeq,
+			ID:          kafkaMsg.UserId,
+			InputFile:   kafkaMsg.FileName,
+			OutputDir:   outputDir,
+			Status:      "pending",
+			CreatedAt:   time.Now(),
+			Profile:     profile,
+			SegmentType: kafkaMsg.SegmentType,
+			Encryption:  kafkaMsg.Encryption,
+			Subtitles:   subtitles,
+
+			DefaultAudio: kafkaMsg.DefaultAudio,
+		}
+
+		// Log encoding progress every 10%
+		// Listeners run sequentially on the goroutine executing ConvertToHLS, so lastStep needs no locking
+		lastStep := -1
+		job.OnProgress(func(job *converter.ConversionJob, progress converter.ConversionProgress) {
+			step := int(progress.Percent) / 10
+			if step == lastStep {
+				return
+			}
+			lastStep = step
+			log.Printf("[KAFKA] Conversion progress for request %s: %.1f%% (speed %.2fx, ETA %.0fs)",
+				videoSeq, progress.Percent, progress.Speed, progress.ETA)
+		})
+
+		return job
 	}
 
-	log.Printf("[KAFKA] Starting HLS conversion for request %s: %s -> %s",
-		kafkaMsg.UserId, kafkaMsg.FileName, outputDir)
+	log.Printf("[KAFKA] Starting HLS conversion for request %s with profile %s: %s -> %s",
+		kafkaMsg.UserId, profile.Name, kafkaMsg.FileName, outputDir)
+
+	// Perform the conversion; a stalled ffmpeg is usually transient, so retry it with a fresh job
+	// (a failed attempt removes its own output before returning, so retries start from a clean directory)
+	job := newJob()
+	err = converter.ConvertToHLS(ctx, job)
+	for attempt := 1; errors.Is(err, converter.ErrJobStalled) && attempt <= k.StallRetries; attempt++ {
+		log.Printf("[KAFKA] Conversion stalled for request %s, retrying (%d/%d): %v",
+			videoSeq, attempt, k.StallRetries, err)
 
-	// Perform the conversion
-	err := converter.ConvertToHLS(job)
+		job = newJob()
+		err = converter.ConvertToHLS(ctx, job)
+	}
+
+	// Shutdown interrupted the conversion; leave the message uncommitted so it is redelivered
+	if errors.Is(err, converter.ErrJobCancelled) {
+		return err
+	}
 
 	// 동적으로 생성된 출력 파일 경로 사용
 	outputFilePath := job.OutputFile
@@ -267,38 +391,85 @@ func (k *KafkaInterface) processMessage(ctx context.Context, m kafka.Message) er
 	}
 
 	completionMsg := CompletionMessage{
-		RequestID:   kafkaMsg.UserId,
-		InputFile:   kafkaMsg.FileName,
-		OutputFile:  outputFilePath,
-		Status:      job.Status,
-		CompletedAt: time.Now(),
+		RequestID:    kafkaMsg.UserId,
+		InputFile:    kafkaMsg.FileName,
+		OutputFile:   outputFilePath,
+		HlsManifest:  outputFilePath,
+		DashManifest: job.DashFile,
+		IFrameLists:  job.IFramePlaylists,
+		Sprites:      job.SpriteFiles,
+		Storyboard:   job.StoryboardFile,
+		Poster:       job.PosterFile,
+		Posters:      job.PosterFiles,
+		PreviewMp4:   job.PreviewFile,
+		PreviewWebp:  job.PreviewWebPFile,
+		SkippedSubs:  job.SkippedSubtitles,
+		AudioOnly:    job.AudioOnly,
+		Profile:      profile.Name,
+		EncodeMode:   job.EncodeMode,
+		Loudness:     job.Loudness,
+		MediaInfo:    job.MediaInfo,
+		SegmentType:  job.SegmentType,
+		Encryption:   job.Encryption,
+		Status:       job.Status,
+		CompletedAt:  time.Now(),
+	}
+
+	if job.Source != nil {
+		completionMsg.Duration = job.Source.Duration
 	}
 
 	if err != nil {
 		completionMsg.Status = "failed"
-		completionMsg.ErrorMessage = err.Error()
-		log.Printf("[KAFKA] Conversion failed for request %s: %v", videoSeq, err)
+		if errors.Is(err, converter.ErrJobTimeout) {
+			completionMsg.Status = job.Status
+		}
+
+		completionMsg.setFailure(err)
+		log.Printf("[KAFKA] Conversion failed for request %s (%s, %s error): %v",
+			videoSeq, job.FailureReason, completionMsg.ErrorType, err)
 	} else {
 		log.Printf("[KAFKA] Conversion completed for request %s: Output file: %s",
 			videoSeq, outputFilePath)
 	}
 
-	// Send completion message if output topic is configured
-	if k.ProducerConn != nil && k.OutputTopic != "" {
-		if err := k.sendCompletionMessage(ctx, completionMsg); err != nil {
-			log.Printf("[KAFKA] Failed to send completion message: %v", err)
-			// Don't return error here, we still want to commit the input message
-		}
-	}
+	k.publishCompletion(ctx, completionMsg)
 
 	return nil
 }
 
+// setFailure fills the failure fields of a completion message from the conversion error
+func (c *CompletionMessage) setFailure(err error) {
+	// Short message for end users; the technical detail goes to errorDetail and the full ffmpeg log stays in the worker log
+	c.Reason = converter.FailureCode(err)
+	c.ErrorMessage = converter.FailureMessage(err)
+	c.ErrorDetail = err.Error()
+	c.ErrorType = "infrastructure"
+	if converter.IsUserError(err) {
+		c.ErrorType = "user"
+	}
+}
+
+// publishCompletion sends the completion message if output topic is configured
+func (k *KafkaInterface) publishCompletion(ctx context.Context, msg CompletionMessage) {
+	if k.ProducerConn == nil || k.OutputTopic == "" {
+		return
+	}
+
+	if err := k.sendCompletionMessage(ctx, msg); err != nil {
+		log.Printf("[KAFKA] Failed to send completion message: %v", err)
+		// Don't return error here, we still want to commit the input message
+	}
+}
+
 // shouldCommitOnError determines if we should commit a message that resulted in error
 // This helps prevent endless reprocessing of bad messages
 func shouldCommitOnError(err error) bool {
 	// Add logic to determine if error is permanent (true) or temporary (false)
 	// For example, file not found or invalid message format are permanent errors
+	if errors.Is(err, converter.ErrJobCancelled) {
+		return false
+	}
 	return true
 }
 
@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/donghquinn/hls_converter/biz/converter"
 	"github.com/donghquinn/hls_converter/configs"
 	"github.com/donghquinn/hls_converter/database"
 	"github.com/donghquinn/hls_converter/kafka"
@@ -24,6 +25,16 @@ func main() {
 	configs.SetGlobalConfiguration()
 	configs.SetDatabaseConfiguration()
 	configs.SetKafkaConfig()
+	configs.SetConverterConfig()
+
+	if profileErr := converter.LoadProfiles(); profileErr != nil {
+		log.Fatalf("Encoding profile Error: %v", profileErr)
+	}
+
+	// Validate the ffmpeg binary before accepting any jobs
+	if _, ffmpegErr := converter.CheckFFmpeg(context.Background(), converter.Profiles()...); ffmpegErr != nil {
+		log.Fatalf("FFmpeg Capability Error: %v", ffmpegErr)
+	}
 
 	dbConn, dbErr := database.InitPostgresConnection()
 
@@ -39,6 +50,8 @@ func main() {
 	// Create directories if they don't exist
 	createDirectories()
 
+	startHealthServer()
+
 	// Create Kafka consumer
 	kafkaInstance, err := kafka.NewKafkaInstance()
 
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+)
+
+// AES-CBC 암호화 후 base64 문자열 반환 (PKCS7 패딩)
+func EncryptAES(plainText []byte, key, iv string) (string, error) {
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return "", fmt.Errorf("AES 키 오류: %v", err)
+	}
+
+	if len(iv) != aes.BlockSize {
+		return "", fmt.Errorf("AES IV 길이 오류: %d", len(iv))
+	}
+
+	padding := aes.BlockSize - len(plainText)%aes.BlockSize
+	padded := append(append([]byte{}, plainText...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	cipherText := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, []byte(iv)).CryptBlocks(cipherText, padded)
+
+	return base64.StdEncoding.EncodeToString(cipherText), nil
+}